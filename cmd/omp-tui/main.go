@@ -4,6 +4,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/rsetiawan7/omp-launcher-tui/internal/cli"
 	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
@@ -44,10 +46,16 @@ func main() {
 			// Define connect-specific flags
 			connectCmd := flag.NewFlagSet("connect", flag.ExitOnError)
 			nickname := connectCmd.String("nickname", "", "Player nickname (overrides config)")
+			wait := connectCmd.Bool("wait", false, "Wait for the game to exit and record the session duration")
+			waitForSlot := connectCmd.Bool("wait-for-slot", false, "Keep polling a full server until a slot is free")
+			retries := connectCmd.Int("retries", 0, "Number of extra queries when the server does not respond")
+			retryInterval := connectCmd.Duration("retry-interval", 5*time.Second, "Delay between queries")
+			timeout := connectCmd.Duration("timeout", 0, "Give up waiting after this long (0 for no limit)")
+			modSet := connectCmd.String("mod-set", "", "Mod set to overlay into GTA for this session (\"none\" to disable)")
 
 			// Check minimum arguments before parsing flags
 			if len(os.Args) < 3 {
-				fmt.Fprintf(os.Stderr, "Usage: %s connect [flags] <alias|host[:port]>\n", os.Args[0])
+				fmt.Fprintf(os.Stderr, "Usage: %s connect [flags] <alias|host[:port]|samp://host:port>\n", os.Args[0])
 				fmt.Fprintf(os.Stderr, "\nFlags:\n")
 				connectCmd.PrintDefaults()
 				fmt.Fprintf(os.Stderr, "\nExamples:\n")
@@ -55,6 +63,9 @@ func main() {
 				fmt.Fprintf(os.Stderr, "  %s connect 127.0.0.1                    # Connect using IP (port defaults to 7777)\n", os.Args[0])
 				fmt.Fprintf(os.Stderr, "  %s connect 127.0.0.1:7777               # Connect using IP with custom port\n", os.Args[0])
 				fmt.Fprintf(os.Stderr, "  %s connect --nickname Player123 my-server  # Connect with custom nickname\n", os.Args[0])
+				fmt.Fprintf(os.Stderr, "  %s connect samp://127.0.0.1:7777?password=secret  # Connect using a server link\n", os.Args[0])
+				fmt.Fprintf(os.Stderr, "  %s connect --wait-for-slot --timeout 10m my-server  # Wait for a free slot\n", os.Args[0])
+				fmt.Fprintf(os.Stderr, "\nExit codes: 1 error, 2 unreachable, 3 timeout, 4 cancelled\n")
 				os.Exit(1)
 			}
 
@@ -67,22 +78,156 @@ func main() {
 			// Get the address argument (last non-flag argument)
 			if connectCmd.NArg() < 1 {
 				fmt.Fprintf(os.Stderr, "Error: server address required\n")
-				fmt.Fprintf(os.Stderr, "Usage: %s connect [flags] <alias|host[:port]>\n", os.Args[0])
+				fmt.Fprintf(os.Stderr, "Usage: %s connect [flags] <alias|host[:port]|samp://host:port>\n", os.Args[0])
 				os.Exit(1)
 			}
 
-			host, port, alias, err := cli.ResolveAddress(connectCmd.Arg(0))
+			target, err := cli.ResolveTarget(connectCmd.Arg(0))
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 				os.Exit(1)
 			}
 
-			if err := cli.Connect(host, port, alias, *nickname); err != nil {
+			// Links may carry a password and nickname; --nickname still wins
+			if *nickname == "" {
+				*nickname = target.Nickname
+			}
+
+			opts := cli.ConnectOptions{
+				Nickname:      *nickname,
+				Password:      target.Password,
+				Wait:          *wait,
+				WaitForSlot:   *waitForSlot,
+				Retries:       *retries,
+				RetryInterval: *retryInterval,
+				Timeout:       *timeout,
+				ModSet:        *modSet,
+			}
+			if err := cli.Connect(target.Host, target.Port, target.Alias, opts); err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
+				os.Exit(cli.ExitCode(err))
 			}
 			return
 
+		case "doctor":
+			doctorCmd := flag.NewFlagSet("doctor", flag.ExitOnError)
+			jsonOutput := doctorCmd.Bool("json", false, "Print results as JSON")
+
+			if err := doctorCmd.Parse(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+				os.Exit(1)
+			}
+
+			if err := cli.Doctor(*jsonOutput); err != nil {
+				fmt.Fprintf(os.Stderr, "\nError: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+
+		case "register-url-handler":
+			if err := cli.RegisterURLHandler(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+
+		case "modset":
+			usage := func() {
+				fmt.Fprintf(os.Stderr, "Usage: %s modset <list|plan <name>|restore>\n", os.Args[0])
+				fmt.Fprintf(os.Stderr, "  list          List configured mod sets\n")
+				fmt.Fprintf(os.Stderr, "  plan <name>   Dry run: show what the mod set would change in the GTA directory\n")
+				fmt.Fprintf(os.Stderr, "  restore       Remove the applied mod set and restore original files\n")
+				os.Exit(1)
+			}
+			if len(os.Args) < 3 {
+				usage()
+			}
+
+			var err error
+			switch os.Args[2] {
+			case "list":
+				err = cli.ModSetList()
+			case "plan":
+				if len(os.Args) < 4 {
+					usage()
+				}
+				err = cli.ModSetPlan(os.Args[3])
+			case "restore":
+				err = cli.ModSetRestore()
+			default:
+				usage()
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+
+		case "blocklist":
+			usage := func() {
+				fmt.Fprintf(os.Stderr, "Usage: %s blocklist <list|add|remove>\n", os.Args[0])
+				fmt.Fprintf(os.Stderr, "  list                                      List block rules\n")
+				fmt.Fprintf(os.Stderr, "  add [--reason text] <type> <value>        Block servers (type: address, ip, cidr, name)\n")
+				fmt.Fprintf(os.Stderr, "  remove <number>                           Remove the rule with the number shown by list\n")
+				fmt.Fprintf(os.Stderr, "\nExamples:\n")
+				fmt.Fprintf(os.Stderr, "  %s blocklist add address 203.0.113.5:7777\n", os.Args[0])
+				fmt.Fprintf(os.Stderr, "  %s blocklist add --reason \"fake players\" cidr 203.0.113.0/24\n", os.Args[0])
+				fmt.Fprintf(os.Stderr, "  %s blocklist add name \"(?i)bot\"\n", os.Args[0])
+				os.Exit(1)
+			}
+			if len(os.Args) < 3 {
+				usage()
+			}
+
+			var err error
+			switch os.Args[2] {
+			case "list":
+				err = cli.BlocklistList()
+			case "add":
+				addCmd := flag.NewFlagSet("blocklist add", flag.ExitOnError)
+				reason := addCmd.String("reason", "", "Why the servers are blocked")
+				if err := addCmd.Parse(os.Args[3:]); err != nil {
+					fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+					os.Exit(1)
+				}
+				if addCmd.NArg() != 2 {
+					usage()
+				}
+				err = cli.BlocklistAdd(addCmd.Arg(0), addCmd.Arg(1), *reason)
+			case "remove":
+				if len(os.Args) < 4 {
+					usage()
+				}
+				number, convErr := strconv.Atoi(os.Args[3])
+				if convErr != nil {
+					usage()
+				}
+				err = cli.BlocklistRemove(number)
+			default:
+				usage()
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+
+		case "history":
+			historyCmd := flag.NewFlagSet("history", flag.ExitOnError)
+			jsonOutput := historyCmd.Bool("json", false, "Print history as JSON")
+			limit := historyCmd.Int("limit", 20, "Number of entries to show (0 for all)")
+
+			if err := historyCmd.Parse(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error parsing flags: %v\n", err)
+				os.Exit(1)
+			}
+
+			if err := cli.History(*jsonOutput, *limit); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+
 		case "export":
 			if len(os.Args) < 3 {
 				fmt.Fprintf(os.Stderr, "Usage: %s export <output-file>\n", os.Args[0])
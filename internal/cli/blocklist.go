@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+)
+
+// BlocklistList prints every block rule with the number used to remove it
+func BlocklistList() error {
+	blocklist, err := config.LoadBlocklist()
+	if err != nil {
+		return fmt.Errorf("failed to load blocklist: %w", err)
+	}
+	if len(blocklist.Rules) == 0 {
+		fmt.Println("The blocklist is empty")
+		return nil
+	}
+
+	fmt.Printf("%-4s %-8s %-32s %s\n", "#", "TYPE", "VALUE", "REASON")
+	for i, rule := range blocklist.Rules {
+		fmt.Printf("%-4d %-8s %-32s %s\n", i+1, rule.Type, rule.Value, rule.Reason)
+	}
+	return nil
+}
+
+// BlocklistAdd adds a block rule of the given type
+func BlocklistAdd(ruleType, value, reason string) error {
+	rule, err := config.NewBlockRule(config.BlockRuleType(ruleType), value, reason)
+	if err != nil {
+		return err
+	}
+	if err := config.AddBlockRule(rule); err != nil {
+		return fmt.Errorf("failed to add rule: %w", err)
+	}
+	fmt.Printf("Blocked %s %s\n", rule.Type, rule.Value)
+	return nil
+}
+
+// BlocklistRemove removes the rule with the number shown by BlocklistList
+func BlocklistRemove(number int) error {
+	if err := config.RemoveBlockRule(number - 1); err != nil {
+		return fmt.Errorf("failed to remove rule: %w", err)
+	}
+	fmt.Printf("Removed rule #%d\n", number)
+	return nil
+}
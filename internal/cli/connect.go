@@ -3,19 +3,77 @@ package cli
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"os/signal"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/gta"
 	"github.com/rsetiawan7/omp-launcher-tui/internal/launcher"
 	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
 )
 
+// ConnectOptions holds optional settings for Connect
+type ConnectOptions struct {
+	// Nickname overrides the nickname from config when not empty
+	Nickname string
+	// Password is used instead of prompting when the server is passworded
+	Password string
+	// Wait keeps the game process supervised to record the session duration
+	Wait bool
+	// WaitForSlot keeps polling a full server until a slot frees up
+	WaitForSlot bool
+	// Retries is the number of extra queries when the server doesn't respond
+	Retries int
+	// RetryInterval is the delay between queries
+	RetryInterval time.Duration
+	// Timeout bounds the whole wait; zero means no limit
+	Timeout time.Duration
+	// ModSet overrides the mod set from favorites and config; "none" disables it
+	ModSet string
+}
+
+var (
+	// ErrUnreachable is returned when the server never answered a query
+	ErrUnreachable = errors.New("server unreachable")
+	// ErrTimeout is returned when --timeout expired before the server was ready
+	ErrTimeout = errors.New("timed out waiting for server")
+	// ErrCancelled is returned when the user interrupted the wait
+	ErrCancelled = errors.New("cancelled")
+)
+
+// Exit codes used by the connect subcommand so scripts can tell failures apart
+const (
+	ExitError       = 1
+	ExitUnreachable = 2
+	ExitTimeout     = 3
+	ExitCancelled   = 4
+)
+
+// ExitCode maps an error returned by Connect to a process exit code
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, ErrCancelled):
+		return ExitCancelled
+	case errors.Is(err, ErrTimeout):
+		return ExitTimeout
+	case errors.Is(err, ErrUnreachable):
+		return ExitUnreachable
+	default:
+		return ExitError
+	}
+}
+
 // Connect connects to a server directly via CLI
-func Connect(host string, port int, alias string, nicknameOverride string) error {
+func Connect(host string, port int, alias string, connectOpts ConnectOptions) error {
 	// Load config to get game path and launcher path
 	cfg, err := config.Load()
 	if err != nil {
@@ -29,8 +87,8 @@ func Connect(host string, port int, alias string, nicknameOverride string) error
 
 	// Use override nickname if provided, otherwise use config nickname
 	nickname := cfg.Nickname
-	if nicknameOverride != "" {
-		nickname = nicknameOverride
+	if connectOpts.Nickname != "" {
+		nickname = connectOpts.Nickname
 	}
 
 	// Query server information
@@ -39,12 +97,17 @@ func Connect(host string, port int, alias string, nicknameOverride string) error
 	} else {
 		fmt.Printf("Connecting to %s:%d...\n", host, port)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	srv, err := server.QueryServer(ctx, host, port)
+	// Ctrl+C only cancels the wait; afterwards it behaves normally again
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	if connectOpts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, connectOpts.Timeout)
+		defer cancel()
+	}
+	srv, err := waitForServer(ctx, server.QueryServer, host, port, connectOpts)
+	stop()
 	if err != nil {
-		return fmt.Errorf("failed to query server: %w", err)
+		return err
 	}
 
 	fmt.Printf("\nServer: %s\n", srv.Name)
@@ -58,8 +121,8 @@ func Connect(host string, port int, alias string, nicknameOverride string) error
 	}
 
 	// If server requires password, prompt for it
-	password := ""
-	if srv.Passworded {
+	password := connectOpts.Password
+	if srv.Passworded && password == "" {
 		fmt.Print("\nEnter server password: ")
 		reader := bufio.NewReader(os.Stdin)
 		input, err := reader.ReadString('\n')
@@ -72,6 +135,16 @@ func Connect(host string, port int, alias string, nicknameOverride string) error
 		}
 	}
 
+	// Warn about installation problems that usually make the client crash
+	if inst, err := gta.Inspect(cfg.GTAPath); err != nil {
+		fmt.Printf("\nWarning: unable to inspect GTA installation: %v\n", err)
+	} else if warnings := inst.Warnings(); len(warnings) > 0 {
+		fmt.Printf("\nWarning: GTA installation problems (%s):\n", inst.Summary())
+		for _, warning := range warnings {
+			fmt.Printf("  - %s\n", warning)
+		}
+	}
+
 	// Launch the game
 	fmt.Printf("\nLaunching game...\n")
 	opts := launcher.LaunchOptions{
@@ -80,6 +153,16 @@ func Connect(host string, port int, alias string, nicknameOverride string) error
 		Nickname: nickname,
 		GTAPath:  cfg.GTAPath,
 		Password: password,
+		Name:     srv.Name,
+		Wait:     connectOpts.Wait,
+		ModSet:   config.ModSetFor(cfg, host, port),
+	}
+	switch connectOpts.ModSet {
+	case "":
+	case "none":
+		opts.ModSet = ""
+	default:
+		opts.ModSet = connectOpts.ModSet
 	}
 
 	err = launcher.Launch(cfg, opts)
@@ -87,30 +170,104 @@ func Connect(host string, port int, alias string, nicknameOverride string) error
 		return fmt.Errorf("failed to launch game: %w", err)
 	}
 
+	if connectOpts.Wait {
+		fmt.Printf("Game exited.\n")
+		return nil
+	}
 	fmt.Printf("Game launched successfully!\n")
 	return nil
 }
 
-// ParseAddress parses an address in the format "host:port" or "host" (defaults to port 7777)
+// queryFunc queries a server, e.g. server.QueryServer
+type queryFunc func(ctx context.Context, host string, port int) (server.Server, error)
+
+// waitForServer queries the server until it responds and, with WaitForSlot,
+// until it has a free slot. Failed queries are retried up to opts.Retries times.
+func waitForServer(ctx context.Context, query queryFunc, host string, port int, opts ConnectOptions) (server.Server, error) {
+	interval := opts.RetryInterval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	failures := 0
+	for {
+		queryCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		srv, err := query(queryCtx, host, port)
+		cancel()
+
+		var message string
+		switch {
+		case ctx.Err() != nil:
+			return server.Server{}, waitError(ctx)
+		case err != nil:
+			failures++
+			// Retrying cannot help a server that cannot be queried at all
+			if failures > opts.Retries || server.ErrorStatus(err) == server.StatusUnsupported {
+				return server.Server{}, fmt.Errorf("%w: failed to query server: %v", ErrUnreachable, err)
+			}
+			message = fmt.Sprintf("No response (attempt %d/%d).", failures, opts.Retries+1)
+		case opts.WaitForSlot && srv.MaxPlayers > 0 && srv.Players >= srv.MaxPlayers:
+			failures = 0
+			message = fmt.Sprintf("Server full (%d/%d).", srv.Players, srv.MaxPlayers)
+		default:
+			return srv, nil
+		}
+
+		if err := countdown(ctx, message, interval); err != nil {
+			return server.Server{}, err
+		}
+	}
+}
+
+// countdown prints a live countdown on a single line and returns early when ctx ends
+func countdown(ctx context.Context, message string, d time.Duration) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	deadline := time.Now().Add(d)
+	for {
+		remaining := time.Until(deadline).Round(time.Second)
+		if remaining <= 0 {
+			fmt.Print("\r\033[K")
+			return nil
+		}
+		fmt.Printf("\r\033[K%s Retrying in %s... (Ctrl+C to cancel)", message, remaining)
+
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return waitError(ctx)
+		case <-ticker.C:
+		}
+	}
+}
+
+func waitError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	return ErrCancelled
+}
+
+// ParseAddress parses an address in the format "host:port", "host", "[ipv6]:port" or "ipv6" (defaults to port 7777)
 func ParseAddress(addr string) (string, int, error) {
-	parts := strings.Split(addr, ":")
+	host, portStr, err := server.SplitAddress(addr)
+	if err != nil {
+		return "", 0, err
+	}
 
-	host := strings.TrimSpace(parts[0])
+	host = strings.TrimSpace(host)
 	if host == "" {
 		return "", 0, fmt.Errorf("host cannot be empty")
 	}
 
 	// Default port to 7777 if not specified
-	port := 7777
-	if len(parts) == 2 {
-		portStr := strings.TrimSpace(parts[1])
-		var err error
-		port, err = strconv.Atoi(portStr)
+	port := server.DefaultPort
+	if portStr != "" {
+		port, err = strconv.Atoi(strings.TrimSpace(portStr))
 		if err != nil {
 			return "", 0, fmt.Errorf("invalid port: %w", err)
 		}
-	} else if len(parts) > 2 {
-		return "", 0, fmt.Errorf("invalid address format. Expected 'host:port' or 'host' (e.g., '127.0.0.1:7777' or '127.0.0.1')")
 	}
 
 	if port < 1 || port > 65535 {
@@ -120,26 +277,105 @@ func ParseAddress(addr string) (string, int, error) {
 	return host, port, nil
 }
 
-// ResolveAddress resolves an address which can be either an alias or host:port or host format
+// ServerURI is a server link such as samp://host:port?password=secret
+type ServerURI struct {
+	Host     string
+	Port     int
+	Password string
+	Nickname string
+}
+
+// uriSchemes lists the link schemes community websites use for servers
+var uriSchemes = []string{"samp", "omp", "openmp", "open.mp"}
+
+// ParseServerURI parses samp://, omp://, openmp:// and open.mp:// links as well as
+// https://open.mp/servers/<host:port> pages. The boolean is false when addr is not a URI.
+func ParseServerURI(addr string) (ServerURI, bool, error) {
+	scheme, rest, ok := strings.Cut(strings.TrimSpace(addr), "://")
+	if !ok {
+		return ServerURI{}, false, nil
+	}
+	scheme = strings.ToLower(scheme)
+
+	u, err := url.Parse(scheme + "://" + rest)
+	if err != nil {
+		return ServerURI{}, true, fmt.Errorf("invalid server link: %w", err)
+	}
+
+	var hostPort string
+	switch {
+	case slices.Contains(uriSchemes, scheme):
+		hostPort = u.Host
+		// Some sites put the address in the path (samp:///1.2.3.4:7777)
+		if hostPort == "" {
+			hostPort = strings.Trim(u.Path, "/")
+		}
+	case (scheme == "http" || scheme == "https") && strings.EqualFold(strings.TrimPrefix(u.Hostname(), "www."), "open.mp"):
+		hostPort = strings.TrimPrefix(strings.Trim(u.Path, "/"), "servers/")
+	default:
+		return ServerURI{}, true, fmt.Errorf("unsupported link scheme %q (expected samp://, omp:// or open.mp://)", scheme)
+	}
+
+	host, port, err := ParseAddress(strings.TrimSuffix(hostPort, "/"))
+	if err != nil {
+		return ServerURI{}, true, err
+	}
+
+	query := u.Query()
+	uri := ServerURI{
+		Host:     host,
+		Port:     port,
+		Password: firstNonEmpty(query.Get("password"), query.Get("pass")),
+		Nickname: firstNonEmpty(query.Get("nickname"), query.Get("name")),
+	}
+	return uri, true, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Target is a server to connect to, as resolved by ResolveTarget
+type Target struct {
+	// ServerURI holds the address, and the password and nickname of a server link
+	ServerURI
+	// Alias is set when the address was a favorite alias
+	Alias string
+}
+
+// ResolveTarget resolves an address which can be either an alias, a server link or host:port or host format
 // If it's an alias, it looks up the favorite server and returns its host:port
-// If it's not an alias, it tries to parse it as host:port or host (defaults to port 7777)
-func ResolveAddress(addr string) (host string, port int, alias string, err error) {
+// If it's a samp://, omp:// or open.mp link, it returns the host:port, password and nickname from the link
+// Otherwise, it tries to parse it as host:port or host (defaults to port 7777)
+func ResolveTarget(addr string) (Target, error) {
+	if uri, ok, err := ParseServerURI(addr); ok {
+		if err != nil {
+			return Target{}, err
+		}
+		return Target{ServerURI: uri}, nil
+	}
+
 	// First, try to find it as an alias in favorites
 	favorites, loadErr := config.LoadFavorites()
 	if loadErr == nil {
 		for _, fav := range favorites.Servers {
 			if fav.Alias == addr {
 				// Found as alias
-				return fav.Host, fav.Port, fav.Alias, nil
+				return Target{ServerURI: ServerURI{Host: fav.Host, Port: fav.Port}, Alias: fav.Alias}, nil
 			}
 		}
 	}
 
 	// Not found as alias, try parsing as host:port or host
-	host, port, err = ParseAddress(addr)
+	host, port, err := ParseAddress(addr)
 	if err != nil {
-		return "", 0, "", err
+		return Target{}, err
 	}
 
-	return host, port, "", nil
+	return Target{ServerURI: ServerURI{Host: host, Port: port}}, nil
 }
@@ -1,7 +1,14 @@
 package cli
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
 )
 
 func TestParseAddress(t *testing.T) {
@@ -20,6 +27,11 @@ func TestParseAddress(t *testing.T) {
 		{"", "", 0, true, "Empty string should error"},
 		{"127.0.0.1:invalid", "", 0, true, "Invalid port should error"},
 		{"127.0.0.1:99999", "", 0, true, "Port out of range should error"},
+		{"[::1]:7777", "::1", 7777, false, "Bracketed IPv6 with port"},
+		{"[2001:db8::1]:8888", "2001:db8::1", 8888, false, "Bracketed IPv6 with custom port"},
+		{"[::1]", "::1", 7777, false, "Bracketed IPv6 without port should default to 7777"},
+		{"::1", "::1", 7777, false, "Bare IPv6 should default to 7777"},
+		{"example.com:1:2", "", 0, true, "Unbracketed address with several colons should error"},
 	}
 
 	for _, tt := range tests {
@@ -44,3 +56,194 @@ func TestParseAddress(t *testing.T) {
 		})
 	}
 }
+
+func TestParseServerURI(t *testing.T) {
+	tests := []struct {
+		input        string
+		wantURI      bool
+		wantHost     string
+		wantPort     int
+		wantPassword string
+		wantNickname string
+		wantErr      bool
+		description  string
+	}{
+		{"127.0.0.1:7777", false, "", 0, "", "", false, "Plain address is not a URI"},
+		{"samp://127.0.0.1:7777", true, "127.0.0.1", 7777, "", "", false, "samp:// with port"},
+		{"samp://example.com", true, "example.com", 7777, "", "", false, "samp:// without port defaults to 7777"},
+		{"omp://127.0.0.1:8888/", true, "127.0.0.1", 8888, "", "", false, "omp:// with trailing slash"},
+		{"open.mp://127.0.0.1:7777", true, "127.0.0.1", 7777, "", "", false, "open.mp:// scheme"},
+		{"SAMP://127.0.0.1:7777", true, "127.0.0.1", 7777, "", "", false, "Scheme is case insensitive"},
+		{"samp://127.0.0.1:7777?password=secret&nickname=Player1", true, "127.0.0.1", 7777, "secret", "Player1", false, "Password and nickname query parameters"},
+		{"https://open.mp/servers/127.0.0.1:7777", true, "127.0.0.1", 7777, "", "", false, "open.mp server page"},
+		{"samp://[::1]:7777", true, "::1", 7777, "", "", false, "samp:// with bracketed IPv6"},
+		{"ftp://127.0.0.1:7777", true, "", 0, "", "", true, "Unsupported scheme should error"},
+		{"samp://127.0.0.1:99999", true, "", 0, "", "", true, "Port out of range should error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			uri, ok, err := ParseServerURI(tt.input)
+
+			if ok != tt.wantURI {
+				t.Fatalf("ParseServerURI(%q) ok = %v, want %v", tt.input, ok, tt.wantURI)
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseServerURI(%q) expected error, got nil", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseServerURI(%q) unexpected error: %v", tt.input, err)
+			}
+			if uri.Host != tt.wantHost || uri.Port != tt.wantPort {
+				t.Errorf("ParseServerURI(%q) = %s:%d, want %s:%d", tt.input, uri.Host, uri.Port, tt.wantHost, tt.wantPort)
+			}
+			if uri.Password != tt.wantPassword {
+				t.Errorf("ParseServerURI(%q) password = %q, want %q", tt.input, uri.Password, tt.wantPassword)
+			}
+			if uri.Nickname != tt.wantNickname {
+				t.Errorf("ParseServerURI(%q) nickname = %q, want %q", tt.input, uri.Nickname, tt.wantNickname)
+			}
+		})
+	}
+}
+
+// fakeQuery answers queries with replies in order, repeating the last one.
+// A nil server in a reply blocks until the query context ends.
+type fakeQuery struct {
+	replies []fakeReply
+	calls   int
+}
+
+type fakeReply struct {
+	srv *server.Server
+	err error
+}
+
+func (f *fakeQuery) query(ctx context.Context, host string, port int) (server.Server, error) {
+	reply := f.replies[min(f.calls, len(f.replies)-1)]
+	f.calls++
+	if reply.srv == nil && reply.err == nil {
+		<-ctx.Done()
+		return server.Server{}, ctx.Err()
+	}
+	if reply.err != nil {
+		return server.Server{}, reply.err
+	}
+	return *reply.srv, nil
+}
+
+func TestWaitForServer(t *testing.T) {
+	up := fakeReply{srv: &server.Server{Name: "Up", Players: 10, MaxPlayers: 50}}
+	full := fakeReply{srv: &server.Server{Name: "Full", Players: 50, MaxPlayers: 50}}
+	down := fakeReply{err: errors.New("no response")}
+	hang := fakeReply{}
+	ipv6 := fakeReply{err: &server.QueryError{Status: server.StatusUnsupported, Err: server.ErrIPv6Unsupported}}
+
+	tests := []struct {
+		replies     []fakeReply
+		opts        ConnectOptions
+		timeout     time.Duration
+		cancelled   bool
+		wantErr     error
+		wantCalls   int
+		description string
+	}{
+		{[]fakeReply{up}, ConnectOptions{}, 0, false, nil, 1, "Server is up"},
+		{[]fakeReply{down, down, up}, ConnectOptions{Retries: 2}, 0, false, nil, 3, "Server comes up within the retries"},
+		{[]fakeReply{down}, ConnectOptions{Retries: 2}, 0, false, ErrUnreachable, 3, "Server never answers"},
+		{[]fakeReply{down}, ConnectOptions{}, 0, false, ErrUnreachable, 1, "No retries"},
+		{[]fakeReply{ipv6}, ConnectOptions{Retries: 3}, 0, false, ErrUnreachable, 1, "IPv6 server is not retried"},
+		{[]fakeReply{full, up}, ConnectOptions{}, 0, false, nil, 1, "Full server without waiting for a slot"},
+		{[]fakeReply{full, full, up}, ConnectOptions{WaitForSlot: true}, 0, false, nil, 3, "Slot frees up"},
+		{[]fakeReply{down, full, down, up}, ConnectOptions{Retries: 1, WaitForSlot: true}, 0, false, nil, 4, "Full server resets the failures"},
+		{[]fakeReply{hang}, ConnectOptions{Retries: 5}, 20 * time.Millisecond, false, ErrTimeout, 1, "Timeout while querying"},
+		{[]fakeReply{full}, ConnectOptions{WaitForSlot: true, RetryInterval: time.Hour}, 20 * time.Millisecond, false, ErrTimeout, 1, "Timeout while waiting for a slot"},
+		{[]fakeReply{up}, ConnectOptions{}, 0, true, ErrCancelled, 1, "Cancelled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.timeout > 0 {
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			if tt.cancelled {
+				cancel()
+			}
+			if tt.opts.RetryInterval == 0 {
+				tt.opts.RetryInterval = time.Millisecond
+			}
+
+			fake := &fakeQuery{replies: tt.replies}
+			srv, err := waitForServer(ctx, fake.query, "127.0.0.1", 7777, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("waitForServer() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && srv.Name == "" {
+				t.Error("waitForServer() returned no server")
+			}
+			if fake.calls != tt.wantCalls {
+				t.Errorf("queries = %d, want %d", fake.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err         error
+		want        int
+		description string
+	}{
+		{nil, 0, "Success"},
+		{errors.New("failed to launch game"), ExitError, "Other error"},
+		{fmt.Errorf("%w: failed to query server: no response", ErrUnreachable), ExitUnreachable, "Unreachable"},
+		{ErrTimeout, ExitTimeout, "Timeout"},
+		{ErrCancelled, ExitCancelled, "Cancelled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveTarget(t *testing.T) {
+	config.UseTempDirForTest(t)
+	if err := config.SaveFavorites(config.Favorites{Servers: []config.FavoriteServer{{Alias: "rp", Host: "203.0.113.5", Port: 7778}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input       string
+		want        Target
+		wantErr     bool
+		description string
+	}{
+		{"samp://127.0.0.1:7777?password=secret&nickname=Player1", Target{ServerURI: ServerURI{Host: "127.0.0.1", Port: 7777, Password: "secret", Nickname: "Player1"}}, false, "Link keeps password and nickname"},
+		{"127.0.0.1", Target{ServerURI: ServerURI{Host: "127.0.0.1", Port: 7777}}, false, "Plain address"},
+		{"rp", Target{ServerURI: ServerURI{Host: "203.0.113.5", Port: 7778}, Alias: "rp"}, false, "Favorite alias"},
+		{"ftp://127.0.0.1", Target{}, true, "Unsupported link"},
+		{"127.0.0.1:99999", Target{}, true, "Invalid address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := ResolveTarget(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveTarget(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveTarget(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/doctor"
+)
+
+// ErrDoctorFailed is returned by Doctor when at least one check failed
+var ErrDoctorFailed = errors.New("one or more checks failed")
+
+// Doctor runs environment diagnostics and prints the results
+func Doctor(jsonOutput bool) error {
+	// Diagnostics must still run when the config file itself is broken
+	// and report the problem as a check, so --json output stays valid
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	checks := append([]doctor.Check{doctor.CheckConfigFile(err)}, doctor.Run(ctx, cfg)...)
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(checks, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal results: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, check := range checks {
+			fmt.Printf("%s %-28s %s\n", statusLabel(check.Status), check.Name, check.Detail)
+			if check.Fix != "" && check.Status != doctor.StatusPass {
+				fmt.Printf("       %-28s → %s\n", "", check.Fix)
+			}
+		}
+	}
+
+	if doctor.HasFailures(checks) {
+		return ErrDoctorFailed
+	}
+	return nil
+}
+
+func statusLabel(status doctor.Status) string {
+	switch status {
+	case doctor.StatusPass:
+		return "[PASS]"
+	case doctor.StatusWarn:
+		return "[WARN]"
+	default:
+		return "[FAIL]"
+	}
+}
@@ -17,9 +17,10 @@ type ExportData struct {
 	Config      config.Config      `json:"config"`
 	Favorites   config.Favorites   `json:"favorites"`
 	MasterLists config.MasterLists `json:"master_lists"`
+	Notes       config.Notes       `json:"notes"`
 }
 
-// Export exports configuration, favorites, and master lists to a single file
+// Export exports configuration, favorites, master lists, and server notes to a single file
 func Export(outputPath string) error {
 	// Load all data
 	cfg, err := config.Load()
@@ -37,6 +38,11 @@ func Export(outputPath string) error {
 		return fmt.Errorf("failed to load master lists: %w", err)
 	}
 
+	notes, err := config.LoadNotes()
+	if err != nil {
+		return fmt.Errorf("failed to load notes: %w", err)
+	}
+
 	// Create export data structure
 	exportData := ExportData{
 		Version:     "1.0",
@@ -44,6 +50,7 @@ func Export(outputPath string) error {
 		Config:      cfg,
 		Favorites:   favorites,
 		MasterLists: masterLists,
+		Notes:       notes,
 	}
 
 	// Marshal to JSON with indentation for readability
@@ -72,14 +79,15 @@ func Export(outputPath string) error {
 	}
 
 	fmt.Println("✓ Export completed successfully!")
-	fmt.Printf("✓ Config, favorites, and master lists exported to: %s\n", absPath)
+	fmt.Printf("✓ Config, favorites, master lists, and notes exported to: %s\n", absPath)
 	fmt.Printf("✓ Total favorites: %d\n", len(favorites.Servers))
 	fmt.Printf("✓ Total master lists: %d\n", len(masterLists.Lists))
+	fmt.Printf("✓ Total server notes: %d\n", len(notes.Servers))
 
 	return nil
 }
 
-// Import imports configuration, favorites, and master lists from a file
+// Import imports configuration, favorites, master lists, and server notes from a file
 func Import(inputPath string) error {
 	// Check if file exists
 	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
@@ -109,6 +117,9 @@ func Import(inputPath string) error {
 	fmt.Printf("- Config settings\n")
 	fmt.Printf("- %d favorite server(s)\n", len(exportData.Favorites.Servers))
 	fmt.Printf("- %d master list(s)\n", len(exportData.MasterLists.Lists))
+	if exportData.Notes.Servers != nil {
+		fmt.Printf("- %d server note(s)\n", len(exportData.Notes.Servers))
+	}
 	fmt.Print("\nDo you want to continue? (y/N): ")
 
 	var response string
@@ -133,6 +144,13 @@ func Import(inputPath string) error {
 		return fmt.Errorf("failed to save master lists: %w", err)
 	}
 
+	// Import notes; exports made before notes existed leave them untouched
+	if exportData.Notes.Servers != nil {
+		if err := config.SaveNotes(exportData.Notes); err != nil {
+			return fmt.Errorf("failed to save notes: %w", err)
+		}
+	}
+
 	// Get absolute path for input
 	absPath, err := filepath.Abs(inputPath)
 	if err != nil {
@@ -143,6 +161,9 @@ func Import(inputPath string) error {
 	fmt.Printf("✓ Data imported from: %s\n", absPath)
 	fmt.Printf("✓ Imported %d favorite(s)\n", len(exportData.Favorites.Servers))
 	fmt.Printf("✓ Imported %d master list(s)\n", len(exportData.MasterLists.Lists))
+	if exportData.Notes.Servers != nil {
+		fmt.Printf("✓ Imported %d server note(s)\n", len(exportData.Notes.Servers))
+	}
 
 	return nil
 }
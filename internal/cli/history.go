@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+// History prints the launch history, most recent first
+func History(jsonOutput bool, limit int) error {
+	history, err := config.LoadHistory()
+	if err != nil {
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+
+	// Reverse so the most recent launch comes first
+	entries := make([]config.HistoryEntry, 0, len(history.Entries))
+	for i := len(history.Entries) - 1; i >= 0; i-- {
+		entries = append(entries, history.Entries[i])
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal history: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No launches recorded yet.")
+		return nil
+	}
+
+	fmt.Printf("%-19s  %-21s  %-16s  %-9s  %-8s  %s\n", "WHEN", "ADDRESS", "NICKNAME", "RUNTIME", "DURATION", "NAME")
+	for _, entry := range entries {
+		duration := "-"
+		if entry.Duration > 0 {
+			duration = entry.Duration.Round(time.Second).String()
+		}
+		fmt.Printf("%-19s  %-21s  %-16s  %-9s  %-8s  %s\n",
+			entry.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			server.JoinAddr(entry.Host, entry.Port),
+			entry.Nickname,
+			entry.Runtime,
+			duration,
+			entry.Name)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/launcher"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/modset"
+)
+
+// ModSetList prints every configured mod set and which one is applied
+func ModSetList() error {
+	sets, err := config.LoadModSets()
+	if err != nil {
+		return fmt.Errorf("failed to load mod sets: %w", err)
+	}
+	if len(sets.Sets) == 0 {
+		path, _ := config.ModSetsPath()
+		fmt.Printf("No mod sets configured. Add them to %s\n", path)
+		return nil
+	}
+
+	applied := modset.Applied()
+	for _, set := range sets.Sets {
+		marker := " "
+		if set.Name == applied {
+			marker = "*"
+		}
+		fmt.Printf("%s %-20s %s", marker, set.Name, set.Source)
+		if set.Description != "" {
+			fmt.Printf("  (%s)", set.Description)
+		}
+		fmt.Println()
+	}
+	if applied != "" {
+		fmt.Printf("\n* currently applied; run 'modset restore' if the game is no longer running\n")
+	}
+	return nil
+}
+
+// ModSetPlan prints a dry-run listing of what applying a mod set would change
+func ModSetPlan(name string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	set, err := config.FindModSet(name)
+	if err != nil {
+		return err
+	}
+	plan, err := modset.BuildPlan(set, cfg.GTAPath)
+	if err != nil {
+		return err
+	}
+	fmt.Print(plan.String())
+	if len(plan.Conflicts) > 0 {
+		return fmt.Errorf("mod set %q has %d conflict(s)", name, len(plan.Conflicts))
+	}
+	return nil
+}
+
+// ModSetRestore removes the applied mod set and restores the original GTA files
+func ModSetRestore() error {
+	applied := modset.Applied()
+	if applied == "" {
+		fmt.Println("No mod set is applied.")
+		return nil
+	}
+	if running, err := launcher.GameRunning(); err == nil && running {
+		return fmt.Errorf("mod set %q is in use: GTA is still running", applied)
+	}
+	if err := modset.Restore(); err != nil {
+		return fmt.Errorf("failed to restore: %w", err)
+	}
+	fmt.Printf("✓ Removed mod set %q and restored the original files\n", applied)
+	return nil
+}
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const desktopFileName = "omp-tui-url-handler.desktop"
+
+// RegisterURLHandler registers omp-tui as the handler for samp:// and omp://
+// links by writing an XDG desktop entry and a mimeapps.list default (Linux only)
+func RegisterURLHandler() error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("register-url-handler is only supported on Linux (running on %s)", runtime.GOOS)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if dataHome == "" || configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		if dataHome == "" {
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+		if configHome == "" {
+			configHome = filepath.Join(home, ".config")
+		}
+	}
+
+	mimeTypes := make([]string, 0, len(uriSchemes))
+	for _, scheme := range uriSchemes {
+		mimeTypes = append(mimeTypes, "x-scheme-handler/"+scheme)
+	}
+
+	// Write the desktop entry
+	appsDir := filepath.Join(dataHome, "applications")
+	if err := os.MkdirAll(appsDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create applications directory: %w", err)
+	}
+	desktopPath := filepath.Join(appsDir, desktopFileName)
+	desktop := fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=Open.MP TUI Launcher
+Comment=Connect to SA-MP and open.mp servers
+Exec="%s" connect %%u
+Terminal=true
+NoDisplay=true
+Categories=Game;
+MimeType=%s;
+`, exe, strings.Join(mimeTypes, ";"))
+	if err := os.WriteFile(desktopPath, []byte(desktop), 0o644); err != nil {
+		return fmt.Errorf("failed to write desktop file: %w", err)
+	}
+	fmt.Printf("✓ Wrote desktop entry: %s\n", desktopPath)
+
+	// Register it as the default handler
+	mimeappsPath := filepath.Join(configHome, "mimeapps.list")
+	if err := updateMimeApps(mimeappsPath, mimeTypes); err != nil {
+		return fmt.Errorf("failed to update mimeapps.list: %w", err)
+	}
+	fmt.Printf("✓ Registered %s in: %s\n", strings.Join(uriSchemes, "://, ")+"://", mimeappsPath)
+
+	// Refresh the desktop database when the tool is available; not fatal otherwise
+	if path, err := exec.LookPath("update-desktop-database"); err == nil {
+		_ = exec.Command(path, appsDir).Run()
+	}
+
+	fmt.Println("\nClicking a samp:// link now opens the connect flow in a terminal.")
+	return nil
+}
+
+// updateMimeApps sets desktopFileName as the default application for mimeTypes,
+// keeping every other line of the file intact
+func updateMimeApps(path string, mimeTypes []string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	pending := make(map[string]bool, len(mimeTypes))
+	for _, mt := range mimeTypes {
+		pending[mt] = true
+	}
+
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+
+	// Replace existing entries in [Default Applications]
+	section := ""
+	sectionEnd := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			section = trimmed
+			continue
+		}
+		if section != "[Default Applications]" {
+			continue
+		}
+		sectionEnd = i
+		key, _, ok := strings.Cut(trimmed, "=")
+		if ok && pending[key] {
+			lines[i] = key + "=" + desktopFileName
+			delete(pending, key)
+		}
+	}
+
+	var additions []string
+	for _, mt := range mimeTypes {
+		if pending[mt] {
+			additions = append(additions, mt+"="+desktopFileName)
+		}
+	}
+
+	switch {
+	case len(additions) == 0:
+	case sectionEnd >= 0:
+		lines = append(lines[:sectionEnd+1], append(additions, lines[sectionEnd+1:]...)...)
+	default:
+		header := -1
+		for i, line := range lines {
+			if strings.TrimSpace(line) == "[Default Applications]" {
+				header = i
+			}
+		}
+		if header >= 0 {
+			lines = append(lines[:header+1], append(additions, lines[header+1:]...)...)
+		} else {
+			lines = append(lines, "[Default Applications]")
+			lines = append(lines, additions...)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+}
@@ -0,0 +1,231 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const BlocklistFile = "blocklist.json"
+
+// BlockRuleType selects what a block rule matches against
+type BlockRuleType string
+
+const (
+	// BlockAddress matches one server by host:port
+	BlockAddress BlockRuleType = "address"
+	// BlockIP matches every server on an IP address
+	BlockIP BlockRuleType = "ip"
+	// BlockCIDR matches every server in an IP range, e.g. 203.0.113.0/24
+	BlockCIDR BlockRuleType = "cidr"
+	// BlockName matches the server name (the SA-MP "hostname") with a regular expression
+	BlockName BlockRuleType = "name"
+)
+
+// BlockRuleTypes lists the rule types in the order the UI offers them
+var BlockRuleTypes = []BlockRuleType{BlockAddress, BlockIP, BlockCIDR, BlockName}
+
+// BlockRule hides servers from the master list
+type BlockRule struct {
+	Type    BlockRuleType `json:"type"`
+	Value   string        `json:"value"`
+	Reason  string        `json:"reason,omitempty"`
+	AddedAt time.Time     `json:"added_at"`
+}
+
+// Blocklist holds every block rule
+type Blocklist struct {
+	Rules []BlockRule `json:"rules"`
+}
+
+// BlocklistPath returns the path to the blocklist file
+func BlocklistPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, BlocklistFile), nil
+}
+
+// LoadBlocklist loads the blocklist from the config directory
+func LoadBlocklist() (Blocklist, error) {
+	path, err := BlocklistPath()
+	if err != nil {
+		return Blocklist{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Blocklist{Rules: []BlockRule{}}, nil
+		}
+		return Blocklist{}, err
+	}
+
+	var blocklist Blocklist
+	if err := json.Unmarshal(data, &blocklist); err != nil {
+		return Blocklist{}, err
+	}
+	return blocklist, nil
+}
+
+// SaveBlocklist saves the blocklist to the config directory
+func SaveBlocklist(blocklist Blocklist) error {
+	path, err := BlocklistPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), DefaultPerms); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(blocklist, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// NewBlockRule validates value for the rule type and returns the rule
+func NewBlockRule(ruleType BlockRuleType, value, reason string) (BlockRule, error) {
+	rule := BlockRule{Type: ruleType, Value: value, Reason: reason, AddedAt: time.Now()}
+	switch ruleType {
+	case BlockAddress:
+		host, port, err := net.SplitHostPort(value)
+		if err != nil {
+			return rule, fmt.Errorf("invalid address %q, expected host:port: %w", value, err)
+		}
+		if _, err := strconv.Atoi(port); err != nil {
+			return rule, fmt.Errorf("invalid port in %q", value)
+		}
+		rule.Value = net.JoinHostPort(host, port)
+	case BlockIP:
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return rule, fmt.Errorf("invalid IP address %q", value)
+		}
+		rule.Value = ip.String()
+	case BlockCIDR:
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return rule, fmt.Errorf("invalid IP range %q: %w", value, err)
+		}
+		rule.Value = network.String()
+	case BlockName:
+		if _, err := regexp.Compile(value); err != nil {
+			return rule, fmt.Errorf("invalid name pattern %q: %w", value, err)
+		}
+	default:
+		return rule, fmt.Errorf("unknown rule type %q (expected address, ip, cidr or name)", ruleType)
+	}
+	return rule, nil
+}
+
+// AddBlockRule appends a rule to the blocklist unless an identical one exists
+func AddBlockRule(rule BlockRule) error {
+	blocklist, err := LoadBlocklist()
+	if err != nil {
+		return err
+	}
+	for _, existing := range blocklist.Rules {
+		if existing.Type == rule.Type && existing.Value == rule.Value {
+			return errors.New("rule already exists")
+		}
+	}
+	blocklist.Rules = append(blocklist.Rules, rule)
+	return SaveBlocklist(blocklist)
+}
+
+// RemoveBlockRule removes the rule at index
+func RemoveBlockRule(index int) error {
+	blocklist, err := LoadBlocklist()
+	if err != nil {
+		return err
+	}
+	if index < 0 || index >= len(blocklist.Rules) {
+		return fmt.Errorf("no rule #%d", index+1)
+	}
+	blocklist.Rules = append(blocklist.Rules[:index], blocklist.Rules[index+1:]...)
+	return SaveBlocklist(blocklist)
+}
+
+// BlockMatcher matches servers against compiled block rules
+type BlockMatcher struct {
+	addresses map[string]BlockRule
+	ips       map[string]BlockRule
+	networks  []*net.IPNet
+	netRules  []BlockRule
+	names     []*regexp.Regexp
+	nameRules []BlockRule
+}
+
+// Matcher compiles the blocklist. Invalid rules, e.g. edited by hand, are skipped.
+func (b Blocklist) Matcher() *BlockMatcher {
+	m := &BlockMatcher{
+		addresses: make(map[string]BlockRule),
+		ips:       make(map[string]BlockRule),
+	}
+	for _, rule := range b.Rules {
+		switch rule.Type {
+		case BlockAddress:
+			m.addresses[rule.Value] = rule
+		case BlockIP:
+			if ip := net.ParseIP(rule.Value); ip != nil {
+				m.ips[ip.String()] = rule
+			}
+		case BlockCIDR:
+			if _, network, err := net.ParseCIDR(rule.Value); err == nil {
+				m.networks = append(m.networks, network)
+				m.netRules = append(m.netRules, rule)
+			}
+		case BlockName:
+			if re, err := regexp.Compile(rule.Value); err == nil {
+				m.names = append(m.names, re)
+				m.nameRules = append(m.nameRules, rule)
+			}
+		}
+	}
+	return m
+}
+
+// Match returns the first rule blocking the server. ip is the resolved address
+// when host is a DNS name and may be empty.
+func (m *BlockMatcher) Match(host string, port int, ip, name string) (BlockRule, bool) {
+	if m == nil {
+		return BlockRule{}, false
+	}
+	if rule, ok := m.addresses[net.JoinHostPort(host, strconv.Itoa(port))]; ok {
+		return rule, true
+	}
+
+	addr := net.ParseIP(host)
+	if addr == nil {
+		addr = net.ParseIP(ip)
+	}
+	if addr != nil {
+		if rule, ok := m.ips[addr.String()]; ok {
+			return rule, true
+		}
+		for i, network := range m.networks {
+			if network.Contains(addr) {
+				return m.netRules[i], true
+			}
+		}
+	}
+
+	if name != "" {
+		for i, re := range m.names {
+			if re.MatchString(name) {
+				return m.nameRules[i], true
+			}
+		}
+	}
+	return BlockRule{}, false
+}
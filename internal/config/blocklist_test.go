@@ -0,0 +1,90 @@
+package config
+
+import "testing"
+
+func TestBlockMatcher(t *testing.T) {
+	blocklist := Blocklist{Rules: []BlockRule{
+		{Type: BlockAddress, Value: "203.0.113.5:7777", Reason: "address"},
+		{Type: BlockAddress, Value: "[2001:db8::1]:7777", Reason: "ipv6 address"},
+		{Type: BlockIP, Value: "198.51.100.7", Reason: "ip"},
+		{Type: BlockCIDR, Value: "192.0.2.0/24", Reason: "cidr"},
+		{Type: BlockName, Value: `(?i)free\s*money`, Reason: "name"},
+		// Invalid entries, e.g. edited by hand, are skipped
+		{Type: BlockIP, Value: "not-an-ip", Reason: "bad ip"},
+		{Type: BlockCIDR, Value: "10.0.0.0/99", Reason: "bad cidr"},
+		{Type: BlockName, Value: "([", Reason: "bad name"},
+		{Type: "port", Value: "7777", Reason: "unknown type"},
+	}}
+	m := blocklist.Matcher()
+
+	tests := []struct {
+		host        string
+		port        int
+		ip          string
+		name        string
+		wantReason  string
+		description string
+	}{
+		{"203.0.113.5", 7777, "", "", "address", "Address"},
+		{"203.0.113.5", 7778, "", "", "", "Address on another port"},
+		{"2001:db8::1", 7777, "", "", "ipv6 address", "IPv6 address"},
+		{"198.51.100.7", 7000, "", "", "ip", "IP on any port"},
+		{"play.example.com", 7777, "198.51.100.7", "", "ip", "IP of a resolved host name"},
+		{"play.example.com", 7777, "", "", "", "Unresolved host name"},
+		{"192.0.2.200", 7777, "", "", "cidr", "IP in range"},
+		{"192.0.3.1", 7777, "", "", "", "IP outside range"},
+		{"203.0.113.9", 7777, "", "FREE  MONEY RP", "name", "Name pattern ignores case"},
+		{"203.0.113.9", 7777, "", "Roleplay", "", "Name not matching"},
+		{"10.1.2.3", 7777, "", "", "", "Invalid range is skipped"},
+		{"203.0.113.9", 7777, "", "([", "", "Invalid pattern is skipped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			rule, ok := m.Match(tt.host, tt.port, tt.ip, tt.name)
+			if ok != (tt.wantReason != "") || rule.Reason != tt.wantReason {
+				t.Errorf("Match() = %q, %v, want %q", rule.Reason, ok, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestNilBlockMatcher(t *testing.T) {
+	var m *BlockMatcher
+	if _, ok := m.Match("203.0.113.5", 7777, "", "name"); ok {
+		t.Error("nil matcher blocked a server")
+	}
+}
+
+func TestNewBlockRule(t *testing.T) {
+	tests := []struct {
+		ruleType    BlockRuleType
+		value       string
+		wantValue   string
+		wantErr     bool
+		description string
+	}{
+		{BlockAddress, "203.0.113.5:7777", "203.0.113.5:7777", false, "Address"},
+		{BlockAddress, "203.0.113.5", "", true, "Address without port"},
+		{BlockAddress, "203.0.113.5:abc", "", true, "Address with bad port"},
+		{BlockIP, "2001:0db8::0001", "2001:db8::1", false, "IP is normalized"},
+		{BlockIP, "not-an-ip", "", true, "Invalid IP"},
+		{BlockCIDR, "192.0.2.77/24", "192.0.2.0/24", false, "Range is normalized"},
+		{BlockCIDR, "192.0.2.0/33", "", true, "Invalid range"},
+		{BlockName, "(?i)free", "(?i)free", false, "Name pattern"},
+		{BlockName, "([", "", true, "Invalid name pattern"},
+		{"port", "7777", "", true, "Unknown type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			rule, err := NewBlockRule(tt.ruleType, tt.value, "")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewBlockRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && rule.Value != tt.wantValue {
+				t.Errorf("Value = %q, want %q", rule.Value, tt.wantValue)
+			}
+		})
+	}
+}
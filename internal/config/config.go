@@ -18,14 +18,62 @@ const (
 )
 
 type Config struct {
-	Nickname          string  `json:"nickname"`
-	GTAPath           string  `json:"gta_path"`
-	OMPLauncher       string  `json:"omp_launcher"`
-	Runtime           Runtime `json:"runtime"`
-	MasterServer      string  `json:"master_server"`
-	BrowseOnly        bool    `json:"browse_only"`
-	CrossOverBottle   string  `json:"crossover_bottle,omitempty"`
-	CrossOverLauncher string  `json:"crossover_launcher,omitempty"`
+	Nickname     string  `json:"nickname"`
+	GTAPath      string  `json:"gta_path"`
+	OMPLauncher  string  `json:"omp_launcher"`
+	Runtime      Runtime `json:"runtime"`
+	MasterServer string  `json:"master_server"`
+	BrowseOnly   bool    `json:"browse_only"`
+	// Theme is a built-in theme (dark, light, high-contrast, monochrome) or a
+	// user theme in the themes directory
+	Theme string `json:"theme,omitempty"`
+	// Keys customizes the keybindings of the server table
+	Keys Keybindings `json:"keys"`
+	// DisableMouse turns off clicking, scrolling and double-click to connect
+	DisableMouse bool `json:"disable_mouse,omitempty"`
+	// HideDetails hides the players, rules, trust and ping panels
+	HideDetails       bool   `json:"hide_details,omitempty"`
+	CrossOverBottle   string `json:"crossover_bottle,omitempty"`
+	CrossOverLauncher string `json:"crossover_launcher,omitempty"`
+	ModSet            string `json:"mod_set,omitempty"`
+	// GeoIPDatabase is a MaxMind-format .mmdb file, e.g. GeoLite2-Country.mmdb
+	GeoIPDatabase string  `json:"geoip_database,omitempty"`
+	Sort          Sort    `json:"sort"`
+	Columns       Columns `json:"columns"`
+	// RecentCommands lists the last command palette commands, most recent first
+	RecentCommands []string `json:"recent_commands,omitempty"`
+}
+
+// Sort is the persisted server list ordering; modes are server.SortMode names
+type Sort struct {
+	Primary             string `json:"primary,omitempty"`
+	Descending          bool   `json:"descending,omitempty"`
+	Secondary           string `json:"secondary,omitempty"`
+	SecondaryDescending bool   `json:"secondary_descending,omitempty"`
+}
+
+// Keybindings picks a key preset ("default", "vim" or "arrows") and overrides
+// single actions, e.g. {"refresh": ["r", "f5"]}. Keys are characters or names
+// such as "enter", "ctrl+r", "f5" and "pgdn"; letters ignore case.
+type Keybindings struct {
+	Preset   string              `json:"preset,omitempty"`
+	Bindings map[string][]string `json:"bindings,omitempty"`
+}
+
+// Column is a server table column. Built-in IDs are e.g. "name" and "ping";
+// "rule:<key>" shows a server rule such as "rule:mapname".
+type Column struct {
+	ID string `json:"id"`
+	// Width caps the column width in characters; 0 sizes it automatically
+	Width int `json:"width,omitempty"`
+}
+
+// Columns holds the table columns of each view, in display order. An empty
+// view uses the default columns.
+type Columns struct {
+	Master    []Column `json:"master,omitempty"`
+	Favorites []Column `json:"favorites,omitempty"`
+	Recent    []Column `json:"recent,omitempty"`
 }
 
 // generateRandomNickname generates a random nickname following SA-MP rules:
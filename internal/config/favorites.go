@@ -5,6 +5,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 )
 
 const FavoritesFile = "favorites.json"
@@ -17,6 +18,8 @@ type FavoriteServer struct {
 	Port        int               `json:"port"`
 	LastUpdated string            `json:"last_updated,omitempty"`
 	Rules       map[string]string `json:"rules,omitempty"`
+	ModSet      string            `json:"mod_set,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
 }
 
 // Favorites holds the list of user favorite servers
@@ -159,3 +162,47 @@ func IsAliasUnique(alias, host string, port int) bool {
 	}
 	return true
 }
+
+// AddFavoriteServers adds the servers that are not favorites yet and adds tags
+// to all of them. It returns how many servers were added.
+func AddFavoriteServers(servers []FavoriteServer, tags []string) (int, error) {
+	favorites, err := LoadFavorites()
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, srv := range servers {
+		i := slices.IndexFunc(favorites.Servers, func(fav FavoriteServer) bool {
+			return fav.Host == srv.Host && fav.Port == srv.Port
+		})
+		if i < 0 {
+			favorites.Servers = append(favorites.Servers, FavoriteServer{Name: srv.Name, Host: srv.Host, Port: srv.Port})
+			i = len(favorites.Servers) - 1
+			added++
+		}
+		for _, tag := range tags {
+			if !slices.Contains(favorites.Servers[i].Tags, tag) {
+				favorites.Servers[i].Tags = append(favorites.Servers[i].Tags, tag)
+			}
+		}
+	}
+	return added, SaveFavorites(favorites)
+}
+
+// RemoveFavoriteServers removes the servers from favorites and returns how
+// many were favorites
+func RemoveFavoriteServers(servers []FavoriteServer) (int, error) {
+	favorites, err := LoadFavorites()
+	if err != nil {
+		return 0, err
+	}
+
+	before := len(favorites.Servers)
+	favorites.Servers = slices.DeleteFunc(favorites.Servers, func(fav FavoriteServer) bool {
+		return slices.ContainsFunc(servers, func(srv FavoriteServer) bool {
+			return fav.Host == srv.Host && fav.Port == srv.Port
+		})
+	})
+	return before - len(favorites.Servers), SaveFavorites(favorites)
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAddFavoriteServers(t *testing.T) {
+	existing := []FavoriteServer{
+		{Name: "Roleplay", Alias: "rp", Host: "203.0.113.5", Port: 7777, ModSet: "hd", Tags: []string{"rp"}},
+	}
+
+	tests := []struct {
+		servers     []FavoriteServer
+		tags        []string
+		wantAdded   int
+		wantTags    map[string][]string
+		description string
+	}{
+		{
+			[]FavoriteServer{{Name: "DM", Host: "203.0.113.6", Port: 7777}},
+			nil, 1,
+			map[string][]string{"203.0.113.5": {"rp"}, "203.0.113.6": nil},
+			"New server without tags",
+		},
+		{
+			[]FavoriteServer{{Host: "203.0.113.5", Port: 7777}, {Host: "203.0.113.6", Port: 7777}},
+			[]string{"eu", "rp"}, 1,
+			map[string][]string{"203.0.113.5": {"rp", "eu"}, "203.0.113.6": {"eu", "rp"}},
+			"Tags are added to existing and new servers once",
+		},
+		{
+			[]FavoriteServer{{Host: "203.0.113.6", Port: 7777}, {Host: "203.0.113.6", Port: 7777}},
+			[]string{"eu"}, 1,
+			map[string][]string{"203.0.113.5": {"rp"}, "203.0.113.6": {"eu"}},
+			"Duplicate servers are added once",
+		},
+		{
+			[]FavoriteServer{{Host: "203.0.113.5", Port: 7777}},
+			nil, 0,
+			map[string][]string{"203.0.113.5": {"rp"}},
+			"Existing server is not added again",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			UseTempDirForTest(t)
+			if err := SaveFavorites(Favorites{Servers: existing}); err != nil {
+				t.Fatal(err)
+			}
+
+			added, err := AddFavoriteServers(tt.servers, tt.tags)
+			if err != nil {
+				t.Fatalf("AddFavoriteServers() error = %v", err)
+			}
+			if added != tt.wantAdded {
+				t.Errorf("added = %d, want %d", added, tt.wantAdded)
+			}
+
+			favorites, err := LoadFavorites()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(favorites.Servers) != len(tt.wantTags) {
+				t.Fatalf("favorites = %+v, want %d servers", favorites.Servers, len(tt.wantTags))
+			}
+			for _, fav := range favorites.Servers {
+				if !slices.Equal(fav.Tags, tt.wantTags[fav.Host]) {
+					t.Errorf("tags of %s = %q, want %q", fav.Host, fav.Tags, tt.wantTags[fav.Host])
+				}
+			}
+			// The existing favorite keeps its alias and mod set
+			if fav := favorites.Servers[0]; fav.Alias != "rp" || fav.ModSet != "hd" || fav.Name != "Roleplay" {
+				t.Errorf("existing favorite = %+v, want its alias, mod set and name kept", fav)
+			}
+		})
+	}
+}
+
+func TestRemoveFavoriteServers(t *testing.T) {
+	existing := []FavoriteServer{
+		{Host: "203.0.113.5", Port: 7777, Tags: []string{"rp"}},
+		{Host: "203.0.113.5", Port: 7778},
+		{Host: "203.0.113.6", Port: 7777, Tags: []string{"dm"}},
+	}
+
+	tests := []struct {
+		servers     []FavoriteServer
+		wantRemoved int
+		wantLeft    []int
+		description string
+	}{
+		{[]FavoriteServer{{Host: "203.0.113.5", Port: 7777}}, 1, []int{1, 2}, "Remove one"},
+		{[]FavoriteServer{{Host: "203.0.113.5", Port: 7777}, {Host: "203.0.113.6", Port: 7777}}, 2, []int{1}, "Remove several"},
+		{[]FavoriteServer{{Host: "203.0.113.9", Port: 7777}}, 0, []int{0, 1, 2}, "Missing server"},
+		{[]FavoriteServer{{Host: "203.0.113.6", Port: 7777}, {Host: "203.0.113.6", Port: 7777}}, 1, []int{0, 1}, "Duplicate servers"},
+		{nil, 0, []int{0, 1, 2}, "Nothing to remove"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			UseTempDirForTest(t)
+			if err := SaveFavorites(Favorites{Servers: existing}); err != nil {
+				t.Fatal(err)
+			}
+
+			removed, err := RemoveFavoriteServers(tt.servers)
+			if err != nil {
+				t.Fatalf("RemoveFavoriteServers() error = %v", err)
+			}
+			if removed != tt.wantRemoved {
+				t.Errorf("removed = %d, want %d", removed, tt.wantRemoved)
+			}
+
+			favorites, err := LoadFavorites()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(favorites.Servers) != len(tt.wantLeft) {
+				t.Fatalf("favorites = %+v, want %d servers", favorites.Servers, len(tt.wantLeft))
+			}
+			for i, idx := range tt.wantLeft {
+				want := existing[idx]
+				got := favorites.Servers[i]
+				if got.Host != want.Host || got.Port != want.Port || !slices.Equal(got.Tags, want.Tags) {
+					t.Errorf("favorite %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	HistoryFile = "history.json"
+
+	// MaxHistoryEntries caps the history file so it doesn't grow forever
+	MaxHistoryEntries = 500
+)
+
+// HistoryEntry records a single launch of the game
+type HistoryEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Name      string    `json:"name,omitempty"`
+	Host      string    `json:"host"`
+	Port      int       `json:"port"`
+	Nickname  string    `json:"nickname"`
+	Runtime   Runtime   `json:"runtime"`
+	// Duration is only known when the launched process was supervised
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// History holds launch history, oldest entry first
+type History struct {
+	Entries []HistoryEntry `json:"entries"`
+}
+
+// HistoryPath returns the path to the history file
+func HistoryPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, HistoryFile), nil
+}
+
+// LoadHistory loads the launch history from the config directory
+func LoadHistory() (History, error) {
+	path, err := HistoryPath()
+	if err != nil {
+		return History{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return History{Entries: []HistoryEntry{}}, nil
+		}
+		return History{}, err
+	}
+
+	var history History
+	if err := json.Unmarshal(data, &history); err != nil {
+		return History{}, err
+	}
+	return history, nil
+}
+
+// SaveHistory saves the launch history, dropping the oldest entries beyond MaxHistoryEntries
+func SaveHistory(history History) error {
+	path, err := HistoryPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), DefaultPerms); err != nil {
+		return err
+	}
+
+	if len(history.Entries) > MaxHistoryEntries {
+		history.Entries = history.Entries[len(history.Entries)-MaxHistoryEntries:]
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// AddHistoryEntry appends an entry to the launch history
+func AddHistoryEntry(entry HistoryEntry) error {
+	history, err := LoadHistory()
+	if err != nil {
+		return err
+	}
+	history.Entries = append(history.Entries, entry)
+	return SaveHistory(history)
+}
+
+// SetHistoryDuration records the session duration of the entry launched at timestamp
+func SetHistoryDuration(timestamp time.Time, duration time.Duration) error {
+	history, err := LoadHistory()
+	if err != nil {
+		return err
+	}
+	for i := len(history.Entries) - 1; i >= 0; i-- {
+		if history.Entries[i].Timestamp.Equal(timestamp) {
+			history.Entries[i].Duration = duration
+			return SaveHistory(history)
+		}
+	}
+	return errors.New("history entry not found")
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+const ModSetsFile = "modsets.json"
+
+type ModSetMode string
+
+const (
+	ModSetModeAuto    ModSetMode = "auto"
+	ModSetModeSymlink ModSetMode = "symlink"
+	ModSetModeCopy    ModSetMode = "copy"
+)
+
+// ModSet is a named directory of files overlaid into the GTA directory before launch.
+// Its layout mirrors the GTA directory, e.g. <Source>/samp.cfg or <Source>/modloader/...
+type ModSet struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	// Sources are further mod directories overlaid together with Source
+	Sources     []string   `json:"sources,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Mode        ModSetMode `json:"mode,omitempty"`
+}
+
+// SourceDirs returns every directory of the mod set, Source first
+func (s ModSet) SourceDirs() []string {
+	var dirs []string
+	if s.Source != "" {
+		dirs = append(dirs, s.Source)
+	}
+	return append(dirs, s.Sources...)
+}
+
+// ModSets holds every configured mod set
+type ModSets struct {
+	Sets []ModSet `json:"sets"`
+}
+
+// ModSetsPath returns the path to the mod sets file
+func ModSetsPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ModSetsFile), nil
+}
+
+// LoadModSets loads the mod sets from the config directory
+func LoadModSets() (ModSets, error) {
+	path, err := ModSetsPath()
+	if err != nil {
+		return ModSets{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ModSets{Sets: []ModSet{}}, nil
+		}
+		return ModSets{}, err
+	}
+
+	var sets ModSets
+	if err := json.Unmarshal(data, &sets); err != nil {
+		return ModSets{}, err
+	}
+	return sets, nil
+}
+
+// SaveModSets saves the mod sets to the config directory
+func SaveModSets(sets ModSets) error {
+	path, err := ModSetsPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), DefaultPerms); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(sets, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// FindModSet returns the mod set with the given name
+func FindModSet(name string) (ModSet, error) {
+	sets, err := LoadModSets()
+	if err != nil {
+		return ModSet{}, err
+	}
+	for _, set := range sets.Sets {
+		if set.Name == name {
+			return set, nil
+		}
+	}
+	return ModSet{}, errors.New("mod set not found: " + name)
+}
+
+// SetFavoriteModSet assigns a mod set to a favorite server; an empty name clears it
+func SetFavoriteModSet(host string, port int, modSet string) error {
+	favorites, err := LoadFavorites()
+	if err != nil {
+		return err
+	}
+	for i := range favorites.Servers {
+		if favorites.Servers[i].Host == host && favorites.Servers[i].Port == port {
+			favorites.Servers[i].ModSet = modSet
+			return SaveFavorites(favorites)
+		}
+	}
+	return errors.New("server is not a favorite")
+}
+
+// ModSetFor returns the mod set to use for a server: the favorite's own set,
+// otherwise the default set from cfg
+func ModSetFor(cfg Config, host string, port int) string {
+	favorites, err := LoadFavorites()
+	if err == nil {
+		for _, fav := range favorites.Servers {
+			if fav.Host == host && fav.Port == port && fav.ModSet != "" {
+				return fav.ModSet
+			}
+		}
+	}
+	return cfg.ModSet
+}
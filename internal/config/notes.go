@@ -0,0 +1,138 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	NotesFile = "notes.json"
+
+	// MaxRating is the highest rating; 0 means unrated
+	MaxRating = 5
+)
+
+// ServerNote holds personal notes about a server
+type ServerNote struct {
+	Note       string     `json:"note,omitempty"`
+	Rating     int        `json:"rating,omitempty"`
+	LastPlayed *time.Time `json:"last_played,omitempty"`
+}
+
+// Empty reports whether the note holds nothing worth keeping
+func (n ServerNote) Empty() bool {
+	return n.Note == "" && n.Rating == 0 && n.LastPlayed == nil
+}
+
+// LastPlayedAt returns when the server was last played, or the zero time
+func (n ServerNote) LastPlayedAt() time.Time {
+	if n.LastPlayed == nil {
+		return time.Time{}
+	}
+	return *n.LastPlayed
+}
+
+// Notes holds server notes keyed by host:port, for any server, not only favorites
+type Notes struct {
+	Servers map[string]ServerNote `json:"servers"`
+}
+
+// NoteKey returns the key of a server in Notes
+func NoteKey(host string, port int) string {
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// Get returns the note of a server
+func (n Notes) Get(host string, port int) (ServerNote, bool) {
+	note, ok := n.Servers[NoteKey(host, port)]
+	return note, ok
+}
+
+// NotesPath returns the path to the notes file
+func NotesPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, NotesFile), nil
+}
+
+// LoadNotes loads server notes from the config directory
+func LoadNotes() (Notes, error) {
+	path, err := NotesPath()
+	if err != nil {
+		return Notes{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Notes{Servers: map[string]ServerNote{}}, nil
+		}
+		return Notes{}, err
+	}
+
+	var notes Notes
+	if err := json.Unmarshal(data, &notes); err != nil {
+		return Notes{}, err
+	}
+	if notes.Servers == nil {
+		notes.Servers = map[string]ServerNote{}
+	}
+	return notes, nil
+}
+
+// SaveNotes saves server notes to the config directory
+func SaveNotes(notes Notes) error {
+	path, err := NotesPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), DefaultPerms); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(notes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// SetServerNote stores the note of a server, removing it when empty
+func SetServerNote(host string, port int, note ServerNote) error {
+	if note.Rating < 0 || note.Rating > MaxRating {
+		return fmt.Errorf("rating must be between 0 and %d", MaxRating)
+	}
+	notes, err := LoadNotes()
+	if err != nil {
+		return err
+	}
+	key := NoteKey(host, port)
+	if note.Empty() {
+		delete(notes.Servers, key)
+	} else {
+		notes.Servers[key] = note
+	}
+	return SaveNotes(notes)
+}
+
+// SetLastPlayed records when a server was last launched
+func SetLastPlayed(host string, port int, played time.Time) error {
+	notes, err := LoadNotes()
+	if err != nil {
+		return err
+	}
+	key := NoteKey(host, port)
+	note := notes.Servers[key]
+	note.LastPlayed = &played
+	notes.Servers[key] = note
+	return SaveNotes(notes)
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readNotesFile(t *testing.T) string {
+	t.Helper()
+	path, err := NotesPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestLoadNotesWithoutFile(t *testing.T) {
+	UseTempDirForTest(t)
+	notes, err := LoadNotes()
+	if err != nil {
+		t.Fatalf("LoadNotes() error = %v", err)
+	}
+	if notes.Servers == nil || len(notes.Servers) != 0 {
+		t.Errorf("Servers = %v, want an empty map", notes.Servers)
+	}
+}
+
+func TestSetServerNote(t *testing.T) {
+	tests := []struct {
+		note        ServerNote
+		wantErr     bool
+		wantStored  bool
+		description string
+	}{
+		{ServerNote{Note: "good admins", Rating: 5}, false, true, "Note with the highest rating"},
+		{ServerNote{Note: "unrated"}, false, true, "Rating 0 means unrated"},
+		{ServerNote{Rating: 1}, false, true, "Rating only"},
+		{ServerNote{Rating: MaxRating + 1}, true, false, "Rating above the maximum"},
+		{ServerNote{Rating: -1}, true, false, "Negative rating"},
+		{ServerNote{}, false, false, "Empty note is removed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			UseTempDirForTest(t)
+			if err := SetServerNote("127.0.0.1", 7777, ServerNote{Note: "old"}); err != nil {
+				t.Fatal(err)
+			}
+
+			err := SetServerNote("127.0.0.1", 7777, tt.note)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetServerNote() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			notes, err := LoadNotes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			note, ok := notes.Get("127.0.0.1", 7777)
+			switch {
+			case tt.wantErr:
+				if note.Note != "old" {
+					t.Errorf("note = %+v after an invalid rating, want the old note kept", note)
+				}
+			case ok != tt.wantStored:
+				t.Errorf("stored = %v, want %v", ok, tt.wantStored)
+			case ok && (note.Note != tt.note.Note || note.Rating != tt.note.Rating):
+				t.Errorf("note = %+v, want %+v", note, tt.note)
+			}
+		})
+	}
+}
+
+func TestSetServerNoteRatingMessage(t *testing.T) {
+	UseTempDirForTest(t)
+	err := SetServerNote("127.0.0.1", 7777, ServerNote{Rating: 9})
+	if err == nil || !strings.Contains(err.Error(), "between 0 and 5") {
+		t.Errorf("SetServerNote() error = %v, want the 0 to 5 range", err)
+	}
+}
+
+func TestSetLastPlayed(t *testing.T) {
+	UseTempDirForTest(t)
+	if err := SetServerNote("127.0.0.1", 7777, ServerNote{Note: "rp", Rating: 4}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(readNotesFile(t), `"rating"`) || strings.Contains(readNotesFile(t), "last_played") {
+		t.Errorf("notes file = %s, want no last_played for a server never played", readNotesFile(t))
+	}
+
+	played := time.Date(2024, 5, 1, 20, 30, 0, 0, time.UTC)
+	if err := SetLastPlayed("127.0.0.1", 7777, played); err != nil {
+		t.Fatalf("SetLastPlayed() error = %v", err)
+	}
+	if err := SetLastPlayed("::1", 7777, played); err != nil {
+		t.Fatalf("SetLastPlayed() error = %v", err)
+	}
+
+	notes, err := LoadNotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	note, _ := notes.Get("127.0.0.1", 7777)
+	if !note.LastPlayedAt().Equal(played) {
+		t.Errorf("LastPlayedAt() = %v, want %v", note.LastPlayedAt(), played)
+	}
+	if note.Note != "rp" || note.Rating != 4 {
+		t.Errorf("note = %+v, want the note and rating kept", note)
+	}
+	if note, ok := notes.Get("::1", 7777); !ok || !note.LastPlayedAt().Equal(played) {
+		t.Errorf("note without notes = %+v, %v, want only the last played time", note, ok)
+	}
+}
@@ -0,0 +1,18 @@
+package config
+
+import "testing"
+
+// UseTempDirForTest points the config directory at a temporary directory for
+// the rest of the test and returns it; the directory itself is not created
+func UseTempDirForTest(t testing.TB) string {
+	t.Helper()
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", root)
+	t.Setenv("HOME", root)
+	t.Setenv("AppData", root)
+	dir, err := ConfigDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
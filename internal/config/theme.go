@@ -0,0 +1,83 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ThemesDir is the config subdirectory holding user themes, one <name>.json each
+const ThemesDir = "themes"
+
+// ThemeFile is a user theme. Colors are names ("yellow") or hex ("#ffcc00");
+// empty colors are taken from Base, a built-in theme.
+type ThemeFile struct {
+	Base          string `json:"base,omitempty"`
+	Background    string `json:"background,omitempty"`
+	Text          string `json:"text,omitempty"`
+	Muted         string `json:"muted,omitempty"`
+	Border        string `json:"border,omitempty"`
+	Title         string `json:"title,omitempty"`
+	Header        string `json:"header,omitempty"`
+	Selection     string `json:"selection,omitempty"`
+	SelectionText string `json:"selection_text,omitempty"`
+	Field         string `json:"field,omitempty"`
+	Good          string `json:"good,omitempty"`
+	Warning       string `json:"warning,omitempty"`
+	Bad           string `json:"bad,omitempty"`
+	Locked        string `json:"locked,omitempty"`
+	Favorite      string `json:"favorite,omitempty"`
+}
+
+// ThemesPath returns the directory holding user themes
+func ThemesPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ThemesDir), nil
+}
+
+// LoadThemeFile loads the user theme called name
+func LoadThemeFile(name string) (ThemeFile, error) {
+	dir, err := ThemesPath()
+	if err != nil {
+		return ThemeFile{}, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, filepath.Base(name)+".json"))
+	if err != nil {
+		return ThemeFile{}, err
+	}
+	var theme ThemeFile
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return ThemeFile{}, err
+	}
+	return theme, nil
+}
+
+// ListThemeFiles returns the names of the user themes, sorted. A missing
+// directory means there are none.
+func ListThemeFiles() ([]string, error) {
+	dir, err := ThemesPath()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
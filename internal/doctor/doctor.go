@@ -0,0 +1,346 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/gta"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/launcher"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Check is the result of a single diagnostic
+type Check struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// Run executes every diagnostic against cfg and returns the results in order
+func Run(ctx context.Context, cfg config.Config) []Check {
+	checks := []Check{checkConfigDir()}
+	checks = append(checks, checkGTAPath(cfg)...)
+	checks = append(checks, checkLauncher(cfg))
+	checks = append(checks, checkRuntimes(cfg)...)
+	checks = append(checks, checkMasterList(ctx, cfg))
+	checks = append(checks, checkCache()...)
+	return checks
+}
+
+// HasFailures reports whether any check failed
+func HasFailures(checks []Check) bool {
+	for _, c := range checks {
+		if c.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckConfigFile reports the error from loading the config file; the other
+// checks then run against the default config
+func CheckConfigFile(loadErr error) Check {
+	check := Check{Name: "Config file"}
+	path, err := config.ConfigPath()
+	if err != nil {
+		path = config.ConfigFile
+	}
+	if loadErr != nil {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("%s: %v; using defaults", path, loadErr)
+		check.Fix = fmt.Sprintf("Fix or delete %s, or run '%s init' to create a new one", path, filepath.Base(os.Args[0]))
+		return check
+	}
+	check.Status = StatusPass
+	check.Detail = path
+	return check
+}
+
+func checkConfigDir() Check {
+	check := Check{Name: "Config directory"}
+	dir, err := config.ConfigDir()
+	if err != nil {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("cannot determine config directory: %v", err)
+		check.Fix = "Set HOME (or XDG_CONFIG_HOME / APPDATA on Windows) for the current user"
+		return check
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("%s: %v", dir, err)
+		check.Fix = fmt.Sprintf("Run '%s init' to create the configuration directory", filepath.Base(os.Args[0]))
+		return check
+	}
+	if !info.IsDir() {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("%s is not a directory", dir)
+		check.Fix = "Remove the file and run init again"
+		return check
+	}
+
+	// Verify write permission by creating a throwaway file
+	probe, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("%s is not writable (mode %s): %v", dir, info.Mode().Perm(), err)
+		check.Fix = fmt.Sprintf("chmod u+rwx %s", dir)
+		return check
+	}
+	probe.Close()
+	_ = os.Remove(probe.Name())
+
+	check.Status = StatusPass
+	check.Detail = fmt.Sprintf("%s (mode %s, writable)", dir, info.Mode().Perm())
+	return check
+}
+
+func checkGTAPath(cfg config.Config) []Check {
+	pathCheck := Check{Name: "GTA path"}
+	if cfg.GTAPath == "" {
+		pathCheck.Status = StatusFail
+		pathCheck.Detail = "gta_path is not configured"
+		pathCheck.Fix = "Set the GTA SA Path in the configuration modal (C) or with 'init --gta-path'"
+		return []Check{pathCheck}
+	}
+	info, err := os.Stat(cfg.GTAPath)
+	if err != nil || !info.IsDir() {
+		pathCheck.Status = StatusFail
+		pathCheck.Detail = fmt.Sprintf("%s is not a directory", cfg.GTAPath)
+		pathCheck.Fix = "Point gta_path at the folder that contains gta_sa.exe"
+		return []Check{pathCheck}
+	}
+	pathCheck.Status = StatusPass
+	pathCheck.Detail = cfg.GTAPath
+
+	inst, err := gta.Inspect(cfg.GTAPath)
+	if err != nil {
+		pathCheck.Status = StatusFail
+		pathCheck.Detail = err.Error()
+		return []Check{pathCheck}
+	}
+
+	exeCheck := Check{Name: gta.ExecutableName}
+	switch {
+	case inst.Executable == nil:
+		exeCheck.Status = StatusFail
+		exeCheck.Detail = fmt.Sprintf("%s not found", filepath.Join(cfg.GTAPath, gta.ExecutableName))
+		exeCheck.Fix = "Make sure gta_path is the GTA San Andreas install folder, not a parent directory"
+	case !inst.Executable.Supported():
+		exeCheck.Status = StatusWarn
+		exeCheck.Detail = executableDetail(*inst.Executable)
+		exeCheck.Fix = "SA-MP and open.mp require the 1.0 US gta_sa.exe; install a 1.0 US downgrade"
+	case !inst.Executable.Verified:
+		exeCheck.Status = StatusWarn
+		exeCheck.Detail = executableDetail(*inst.Executable)
+		exeCheck.Fix = "Only the size matches 1.0 US, so the file may be patched; reinstall a clean 1.0 US downgrade if the client crashes"
+	default:
+		exeCheck.Status = StatusPass
+		exeCheck.Detail = executableDetail(*inst.Executable)
+	}
+
+	clientCheck := Check{Name: "Multiplayer client"}
+	if inst.SAMP == nil && inst.OpenMP == nil {
+		clientCheck.Status = StatusWarn
+		clientCheck.Detail = "no samp.dll or omp-client.dll in the GTA directory"
+		clientCheck.Fix = "Install the SA-MP client, or make sure the open.mp launcher can install its client"
+	} else {
+		clientCheck.Status = StatusPass
+		clientCheck.Detail = inst.Summary()
+	}
+
+	checks := []Check{pathCheck, exeCheck, clientCheck}
+	for _, mod := range inst.Mods {
+		checks = append(checks, Check{
+			Name:   "Incompatible mod",
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("%s: %s", mod.Path, mod.Reason),
+			Fix:    "Remove or disable the mod before connecting",
+		})
+	}
+	for _, mod := range inst.ModWarnings {
+		checks = append(checks, Check{
+			Name:   "Mod",
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("%s: %s", mod.Path, mod.Reason),
+			Fix:    "Disable the mod if a server kicks you for it",
+		})
+	}
+	return checks
+}
+
+func executableDetail(exe gta.Executable) string {
+	return fmt.Sprintf("version %s (%d bytes, sha256 %s)", exe.Label(), exe.Size, exe.SHA256[:12])
+}
+
+func checkLauncher(cfg config.Config) Check {
+	check := Check{Name: "open.mp launcher"}
+	if cfg.OMPLauncher == "" {
+		check.Status = StatusFail
+		check.Detail = "omp_launcher is not configured"
+		check.Fix = "Set the open.mp Launcher path in the configuration modal (C) or with 'init --omp-launcher'"
+		return check
+	}
+	path := launcher.ResolveLauncherPath(cfg.OMPLauncher)
+	if path == "" {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("no omp-launcher executable found at %s", cfg.OMPLauncher)
+		check.Fix = "Point omp_launcher at omp-launcher.exe or the directory that contains it"
+		return check
+	}
+	check.Status = StatusPass
+	check.Detail = path
+	return check
+}
+
+func checkRuntimes(cfg config.Config) []Check {
+	var checks []Check
+	for _, candidate := range launcher.RuntimeCandidates() {
+		check := Check{Name: fmt.Sprintf("Runtime candidate: %s", candidate.Runtime)}
+		if candidate.Available {
+			check.Status = StatusPass
+			check.Detail = "available"
+			if candidate.Path != "" {
+				check.Detail = candidate.Path
+			}
+		} else {
+			check.Status = StatusWarn
+			check.Detail = candidate.Reason
+		}
+		checks = append(checks, check)
+	}
+
+	selected := Check{Name: "Runtime selection"}
+	rt, err := launcher.DetectRuntime(cfg)
+	if err != nil {
+		selected.Status = StatusFail
+		selected.Detail = err.Error()
+		selected.Fix = "Install Wine or Proton (or CrossOver on macOS) and make sure it is in PATH"
+		return append(checks, selected)
+	}
+	selected.Status = StatusPass
+	selected.Detail = fmt.Sprintf("%s (configured: %s)", rt, cfg.Runtime)
+	if cfg.Runtime != config.RuntimeAuto && cfg.Runtime != "" {
+		reason := "not supported on this platform"
+		for _, candidate := range launcher.RuntimeCandidates() {
+			if candidate.Runtime == rt {
+				reason = candidate.Reason
+				if candidate.Available {
+					reason = ""
+				}
+			}
+		}
+		if reason != "" {
+			selected.Status = StatusWarn
+			selected.Detail = fmt.Sprintf("%s is configured but not available: %s", rt, reason)
+			selected.Fix = "Install the configured runtime or set runtime to auto"
+		}
+	}
+	return append(checks, selected)
+}
+
+func checkMasterList(ctx context.Context, cfg config.Config) Check {
+	check := Check{Name: "Master list"}
+	masterURL := cfg.MasterServer
+	if active, err := config.GetActiveMasterList(); err == nil && active != "" {
+		masterURL = active
+	}
+
+	if err := server.TestMasterServer(ctx, masterURL); err != nil {
+		fallback := server.DefaultFallbackPath()
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("%s: %v", masterURL, err)
+		check.Fix = fmt.Sprintf("Check your connection, pick another master list (M), or provide %s", fallback)
+		if _, statErr := os.Stat(fallback); statErr == nil {
+			check.Status = StatusWarn
+			check.Fix = fmt.Sprintf("Fallback list %s will be used; check your connection or pick another master list (M)", fallback)
+		}
+		return check
+	}
+	check.Status = StatusPass
+	check.Detail = fmt.Sprintf("%s reachable", masterURL)
+	return check
+}
+
+func checkCache() []Check {
+	checks := []Check{checkCacheFile()}
+
+	favorites := Check{Name: "Favorites file"}
+	if favs, err := config.LoadFavorites(); err != nil {
+		favorites.Status = StatusFail
+		favorites.Detail = err.Error()
+		favorites.Fix = "Fix or delete the favorites file; a new one is created automatically"
+	} else {
+		favorites.Status = StatusPass
+		favorites.Detail = fmt.Sprintf("%d favorite(s)", len(favs.Servers))
+	}
+	checks = append(checks, favorites)
+
+	masterLists := Check{Name: "Master lists file"}
+	if lists, err := config.LoadMasterLists(); err != nil {
+		masterLists.Status = StatusFail
+		masterLists.Detail = err.Error()
+		masterLists.Fix = "Fix or delete the master lists file; the official list is restored automatically"
+	} else {
+		masterLists.Status = StatusPass
+		masterLists.Detail = fmt.Sprintf("%d master list(s)", len(lists.Lists))
+	}
+	return append(checks, masterLists)
+}
+
+func checkCacheFile() Check {
+	check := Check{Name: "Server cache"}
+	path, err := config.CachePath()
+	if err != nil {
+		check.Status = StatusFail
+		check.Detail = err.Error()
+		return check
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			check.Status = StatusWarn
+			check.Detail = "no cache yet"
+			check.Fix = "The cache is written after the first refresh in the TUI"
+			return check
+		}
+		check.Status = StatusFail
+		check.Detail = err.Error()
+		return check
+	}
+
+	var cache server.ServerCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		check.Status = StatusFail
+		check.Detail = fmt.Sprintf("corrupt cache: %v", err)
+		check.Fix = fmt.Sprintf("Delete %s; it is rebuilt on the next refresh", path)
+		return check
+	}
+
+	age := time.Since(cache.UpdatedAt).Round(time.Minute)
+	check.Detail = fmt.Sprintf("%d servers, updated %s ago", len(cache.Servers), age)
+	if age > time.Hour {
+		check.Status = StatusWarn
+		check.Fix = "Cache is stale and will be ignored; press R in the TUI to refresh"
+		return check
+	}
+	check.Status = StatusPass
+	return check
+}
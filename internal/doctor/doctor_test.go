@@ -0,0 +1,235 @@
+package doctor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+// writeFile creates path with content, extended to size bytes when size is set
+func writeFile(t *testing.T, path, content string, size int64) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if size > 0 {
+		if err := os.Truncate(path, size); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func statuses(checks []Check) []Status {
+	out := make([]Status, len(checks))
+	for i, c := range checks {
+		out[i] = c.Status
+	}
+	return out
+}
+
+func TestCheckConfigDir(t *testing.T) {
+	tests := []struct {
+		setup       func(t *testing.T, dir string)
+		want        Status
+		description string
+	}{
+		{func(t *testing.T, dir string) {}, StatusFail, "Missing directory"},
+		{func(t *testing.T, dir string) { writeFile(t, dir, "", 0) }, StatusFail, "File instead of a directory"},
+		{func(t *testing.T, dir string) { writeFile(t, filepath.Join(dir, config.ConfigFile), "{}", 0) }, StatusPass, "Writable directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			dir := config.UseTempDirForTest(t)
+			tt.setup(t, dir)
+			check := checkConfigDir()
+			if check.Status != tt.want {
+				t.Errorf("status = %s (%s), want %s", check.Status, check.Detail, tt.want)
+			}
+			if check.Status == StatusFail && check.Fix == "" {
+				t.Error("failed check has no fix")
+			}
+		})
+	}
+}
+
+func TestCheckConfigFile(t *testing.T) {
+	tests := []struct {
+		content     string
+		want        Status
+		description string
+	}{
+		{"", StatusPass, "No config file yet"},
+		{`{"nickname":"Player"}`, StatusPass, "Valid config"},
+		{`{"nickname":`, StatusFail, "Corrupt config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			dir := config.UseTempDirForTest(t)
+			if tt.content != "" {
+				writeFile(t, filepath.Join(dir, config.ConfigFile), tt.content, 0)
+			}
+			_, err := config.Load()
+			check := CheckConfigFile(err)
+			if check.Status != tt.want {
+				t.Errorf("status = %s (%s), want %s", check.Status, check.Detail, tt.want)
+			}
+			if check.Status == StatusFail && check.Fix == "" {
+				t.Error("failed check has no fix")
+			}
+		})
+	}
+}
+
+func TestCheckGTAPath(t *testing.T) {
+	const supportedSize = 14383616
+
+	tests := []struct {
+		files       map[string]int64
+		path        string
+		want        []Status
+		description string
+	}{
+		{nil, "-", []Status{StatusFail}, "No GTA path configured"},
+		{nil, "missing", []Status{StatusFail}, "GTA path does not exist"},
+		{map[string]int64{"gta_sa.exe": supportedSize}, "file", []Status{StatusFail}, "GTA path points at a file"},
+		{map[string]int64{"samp.dll": 1}, "", []Status{StatusPass, StatusFail, StatusPass}, "Missing gta_sa.exe"},
+		{map[string]int64{"gta_sa.exe": 1234, "samp.dll": 1}, "", []Status{StatusPass, StatusWarn, StatusPass}, "Unknown gta_sa.exe version"},
+		{map[string]int64{"gta_sa.exe": supportedSize}, "", []Status{StatusPass, StatusWarn, StatusWarn}, "No multiplayer client"},
+		{map[string]int64{"gta_sa.exe": supportedSize, "omp-client.dll": 1}, "", []Status{StatusPass, StatusWarn, StatusPass}, "1.0 US by size only is unverified"},
+		{map[string]int64{"gta_sa.exe": supportedSize, "samp.dll": 1, "SAMPFUNCS.asi": 1}, "", []Status{StatusPass, StatusWarn, StatusPass, StatusWarn}, "Incompatible mod"},
+		{map[string]int64{"gta_sa.exe": supportedSize, "samp.dll": 1, "scripts/CLEO.asi": 1}, "", []Status{StatusPass, StatusWarn, StatusPass, StatusWarn}, "CLEO is a mod warning"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			gtaPath := t.TempDir()
+			for name, size := range tt.files {
+				writeFile(t, filepath.Join(gtaPath, filepath.FromSlash(name)), "x", size)
+			}
+			switch tt.path {
+			case "-":
+				gtaPath = ""
+			case "missing":
+				gtaPath = filepath.Join(gtaPath, "missing")
+			case "file":
+				gtaPath = filepath.Join(gtaPath, "gta_sa.exe")
+			}
+
+			checks := checkGTAPath(config.Config{GTAPath: gtaPath})
+			if got := statuses(checks); !slices.Equal(got, tt.want) {
+				t.Errorf("statuses = %v, want %v (%+v)", got, tt.want, checks)
+			}
+		})
+	}
+}
+
+func TestCheckLauncher(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "omp-launcher.exe")
+	writeFile(t, exe, "x", 0)
+
+	tests := []struct {
+		launcher    string
+		want        Status
+		description string
+	}{
+		{"", StatusFail, "Not configured"},
+		{filepath.Join(dir, "missing.exe"), StatusFail, "Missing executable"},
+		{t.TempDir(), StatusFail, "Directory without the launcher"},
+		{exe, StatusPass, "Executable"},
+		{dir, StatusPass, "Directory with the launcher"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			check := checkLauncher(config.Config{OMPLauncher: tt.launcher})
+			if check.Status != tt.want {
+				t.Errorf("status = %s (%s), want %s", check.Status, check.Detail, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCacheFile(t *testing.T) {
+	cache := func(updated time.Time) string {
+		data, err := json.Marshal(server.ServerCache{Servers: []server.Server{{Host: "127.0.0.1", Port: 7777}}, UpdatedAt: updated})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	tests := []struct {
+		content     string
+		want        Status
+		description string
+	}{
+		{"", StatusWarn, "No cache yet"},
+		{"{not json", StatusFail, "Corrupt cache"},
+		{cache(time.Now().Add(-2 * time.Hour)), StatusWarn, "Stale cache"},
+		{cache(time.Now()), StatusPass, "Fresh cache"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			dir := config.UseTempDirForTest(t)
+			if tt.content != "" {
+				writeFile(t, filepath.Join(dir, config.CacheFile), tt.content, 0)
+			}
+			check := checkCacheFile()
+			if check.Status != tt.want {
+				t.Errorf("status = %s (%s), want %s", check.Status, check.Detail, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCache(t *testing.T) {
+	tests := []struct {
+		favorites   string
+		masterLists string
+		want        []Status
+		description string
+	}{
+		{"", "", []Status{StatusWarn, StatusPass, StatusPass}, "No files yet"},
+		{`{"servers":[{"host":"127.0.0.1","port":7777}]}`, `{"lists":[]}`, []Status{StatusWarn, StatusPass, StatusPass}, "Valid files"},
+		{"{not json", "", []Status{StatusWarn, StatusFail, StatusPass}, "Corrupt favorites"},
+		{"", `{"lists":"bad"}`, []Status{StatusWarn, StatusPass, StatusFail}, "Corrupt master lists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			dir := config.UseTempDirForTest(t)
+			if tt.favorites != "" {
+				writeFile(t, filepath.Join(dir, config.FavoritesFile), tt.favorites, 0)
+			}
+			if tt.masterLists != "" {
+				writeFile(t, filepath.Join(dir, config.MasterListFile), tt.masterLists, 0)
+			}
+			checks := checkCache()
+			if got := statuses(checks); !slices.Equal(got, tt.want) {
+				t.Errorf("statuses = %v, want %v (%+v)", got, tt.want, checks)
+			}
+		})
+	}
+}
+
+func TestHasFailures(t *testing.T) {
+	if HasFailures([]Check{{Status: StatusPass}, {Status: StatusWarn}}) {
+		t.Error("HasFailures() = true for warnings only")
+	}
+	if !HasFailures([]Check{{Status: StatusPass}, {Status: StatusFail}}) {
+		t.Error("HasFailures() = false with a failed check")
+	}
+}
@@ -0,0 +1,196 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Data section field types
+const (
+	typeExtended = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat
+)
+
+// maxDepth bounds nesting so a corrupt file can't recurse forever
+const maxDepth = 64
+
+var errTruncated = errors.New("invalid database: unexpected end of data")
+
+// decoder decodes the MaxMind DB data section format into Go values:
+// map[string]any, []any, string, []byte, float64, uint64, int32 and bool
+type decoder struct {
+	buf   []byte
+	depth int
+}
+
+// decode decodes the field at offset, returning it and the offset after it
+func (d *decoder) decode(offset int) (any, int, error) {
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > maxDepth {
+		return nil, 0, errors.New("invalid database: data nested too deeply")
+	}
+
+	typ, size, offset, err := d.control(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if typ == typePointer {
+		target, next, err := d.pointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(target)
+		return value, next, err
+	}
+
+	switch typ {
+	case typeMap:
+		m := make(map[string]any, size)
+		for i := 0; i < size; i++ {
+			key, next, err := d.decode(offset)
+			if err != nil {
+				return nil, 0, err
+			}
+			k, ok := key.(string)
+			if !ok {
+				return nil, 0, errors.New("invalid database: map key is not a string")
+			}
+			if m[k], offset, err = d.decode(next); err != nil {
+				return nil, 0, err
+			}
+		}
+		return m, offset, nil
+	case typeArray:
+		a := make([]any, size)
+		for i := range a {
+			if a[i], offset, err = d.decode(offset); err != nil {
+				return nil, 0, err
+			}
+		}
+		return a, offset, nil
+	case typeBool:
+		return size != 0, offset, nil
+	}
+
+	if offset+size > len(d.buf) {
+		return nil, 0, errTruncated
+	}
+	b := d.buf[offset : offset+size]
+	next := offset + size
+
+	switch typ {
+	case typeString:
+		return string(b), next, nil
+	case typeBytes, typeUint128:
+		return append([]byte(nil), b...), next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid database: double of size %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid database: float of size %d", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), next, nil
+	case typeUint16, typeUint32, typeUint64:
+		var n uint64
+		for _, c := range b {
+			n = n<<8 | uint64(c)
+		}
+		return n, next, nil
+	case typeInt32:
+		var n uint32
+		for _, c := range b {
+			n = n<<8 | uint32(c)
+		}
+		return int32(n), next, nil
+	}
+	return nil, 0, fmt.Errorf("invalid database: unknown field type %d", typ)
+}
+
+// control reads a field's control byte, returning its type, payload size and
+// the offset of the payload
+func (d *decoder) control(offset int) (int, int, int, error) {
+	if offset < 0 || offset >= len(d.buf) {
+		return 0, 0, 0, errTruncated
+	}
+	ctrl := d.buf[offset]
+	offset++
+
+	typ := int(ctrl >> 5)
+	if typ == typeExtended {
+		if offset >= len(d.buf) {
+			return 0, 0, 0, errTruncated
+		}
+		typ = 7 + int(d.buf[offset])
+		offset++
+	}
+
+	size := int(ctrl & 0x1f)
+	if typ == typePointer {
+		// Pointers keep the raw bits; pointer() interprets them
+		return typ, size, offset, nil
+	}
+	if size >= 29 {
+		n := size - 28
+		if offset+n > len(d.buf) {
+			return 0, 0, 0, errTruncated
+		}
+		extra := 0
+		for _, c := range d.buf[offset : offset+n] {
+			extra = extra<<8 | int(c)
+		}
+		offset += n
+		switch size {
+		case 29:
+			size = 29 + extra
+		case 30:
+			size = 285 + extra
+		default:
+			size = 65821 + extra
+		}
+	}
+	return typ, size, offset, nil
+}
+
+// pointer decodes a pointer from the size bits of its control byte and the
+// following bytes, returning the target offset and the offset after the pointer
+func (d *decoder) pointer(bits, offset int) (int, int, error) {
+	n := bits>>3 + 1
+	if offset+n > len(d.buf) {
+		return 0, 0, errTruncated
+	}
+	value := 0
+	if n < 4 {
+		value = bits & 0x7
+	}
+	for _, c := range d.buf[offset : offset+n] {
+		value = value<<8 | int(c)
+	}
+	switch n {
+	case 2:
+		value += 2048
+	case 3:
+		value += 526336
+	}
+	return value, offset + n, nil
+}
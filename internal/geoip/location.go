@@ -0,0 +1,51 @@
+package geoip
+
+import "net"
+
+// Location is where an IP address is registered, as far as the database knows
+type Location struct {
+	// CountryCode is the ISO 3166-1 alpha-2 code, e.g. "DE"
+	CountryCode string
+	Country     string
+	// Region is the first subdivision, e.g. a state; only city databases have it
+	Region string
+}
+
+// Known reports whether the country was found
+func (l Location) Known() bool {
+	return l.CountryCode != ""
+}
+
+// Locate returns the country and region of ip. The registered country is used
+// when the database has no physical location, e.g. for anycast networks.
+func (r *Reader) Locate(ip net.IP) (Location, error) {
+	record, err := r.Lookup(ip)
+	if err != nil || record == nil {
+		return Location{}, err
+	}
+
+	var loc Location
+	for _, key := range []string{"country", "registered_country"} {
+		country, ok := record[key].(map[string]any)
+		if !ok {
+			continue
+		}
+		loc.CountryCode, _ = country["iso_code"].(string)
+		loc.Country = englishName(country)
+		if loc.Known() {
+			break
+		}
+	}
+	if subdivisions, ok := record["subdivisions"].([]any); ok && len(subdivisions) > 0 {
+		if region, ok := subdivisions[0].(map[string]any); ok {
+			loc.Region = englishName(region)
+		}
+	}
+	return loc, nil
+}
+
+func englishName(entry map[string]any) string {
+	names, _ := entry["names"].(map[string]any)
+	name, _ := names["en"].(string)
+	return name
+}
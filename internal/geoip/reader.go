@@ -0,0 +1,188 @@
+// Package geoip reads MaxMind DB (.mmdb) files such as GeoLite2-Country and
+// GeoLite2-City. It works entirely offline on a database supplied by the user.
+package geoip
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+)
+
+// metadataMarker precedes the metadata section at the end of the file
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparator is the number of zero bytes between the search tree and the data section
+const dataSectionSeparator = 16
+
+// Metadata describes a database
+type Metadata struct {
+	DatabaseType string
+	IPVersion    int
+	NodeCount    int
+	RecordSize   int
+	BuildEpoch   uint64
+}
+
+// Reader looks up IP addresses in a MaxMind DB file held in memory
+type Reader struct {
+	Metadata Metadata
+
+	tree      []byte
+	data      []byte
+	ipv4Start int
+}
+
+// Open reads the database at path
+func Open(path string) (*Reader, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return FromBytes(buf)
+}
+
+// FromBytes parses a database already read into memory
+func FromBytes(buf []byte) (*Reader, error) {
+	start := bytes.LastIndex(buf, metadataMarker)
+	if start < 0 {
+		return nil, errors.New("not a MaxMind DB file: metadata not found")
+	}
+	meta, err := decodeMetadata(buf[start+len(metadataMarker):])
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata: %w", err)
+	}
+
+	treeSize := meta.NodeCount * meta.RecordSize * 2 / 8
+	if treeSize+dataSectionSeparator > start {
+		return nil, errors.New("invalid database: search tree exceeds file size")
+	}
+
+	r := &Reader{
+		Metadata: meta,
+		tree:     buf[:treeSize],
+		data:     buf[treeSize+dataSectionSeparator : start],
+	}
+
+	// IPv4 addresses live under ::/96 in IPv6 databases
+	if meta.IPVersion == 6 {
+		node := 0
+		for i := 0; i < 96 && node < meta.NodeCount; i++ {
+			if node, err = r.record(node, 0); err != nil {
+				return nil, err
+			}
+		}
+		r.ipv4Start = node
+	}
+	return r, nil
+}
+
+func decodeMetadata(buf []byte) (Metadata, error) {
+	d := decoder{buf: buf}
+	value, _, err := d.decode(0)
+	if err != nil {
+		return Metadata{}, err
+	}
+	fields, ok := value.(map[string]any)
+	if !ok {
+		return Metadata{}, errors.New("metadata is not a map")
+	}
+
+	meta := Metadata{
+		IPVersion:  int(toUint(fields["ip_version"])),
+		NodeCount:  int(toUint(fields["node_count"])),
+		RecordSize: int(toUint(fields["record_size"])),
+		BuildEpoch: toUint(fields["build_epoch"]),
+	}
+	meta.DatabaseType, _ = fields["database_type"].(string)
+
+	switch meta.RecordSize {
+	case 24, 28, 32:
+	default:
+		return Metadata{}, fmt.Errorf("unsupported record size %d", meta.RecordSize)
+	}
+	if meta.IPVersion != 4 && meta.IPVersion != 6 {
+		return Metadata{}, fmt.Errorf("unsupported IP version %d", meta.IPVersion)
+	}
+	return meta, nil
+}
+
+// record returns the left (bit 0) or right (bit 1) record of a search tree node
+func (r *Reader) record(node int, bit uint) (int, error) {
+	size := r.Metadata.RecordSize * 2 / 8
+	base := node * size
+	if base+size > len(r.tree) {
+		return 0, errors.New("invalid database: node outside search tree")
+	}
+	b := r.tree[base : base+size]
+
+	switch r.Metadata.RecordSize {
+	case 24:
+		if bit == 0 {
+			return int(b[0])<<16 | int(b[1])<<8 | int(b[2]), nil
+		}
+		return int(b[3])<<16 | int(b[4])<<8 | int(b[5]), nil
+	case 28:
+		if bit == 0 {
+			return int(b[3]&0xF0)<<20 | int(b[0])<<16 | int(b[1])<<8 | int(b[2]), nil
+		}
+		return int(b[3]&0x0F)<<24 | int(b[4])<<16 | int(b[5])<<8 | int(b[6]), nil
+	default:
+		if bit == 0 {
+			return int(b[0])<<24 | int(b[1])<<16 | int(b[2])<<8 | int(b[3]), nil
+		}
+		return int(b[4])<<24 | int(b[5])<<16 | int(b[6])<<8 | int(b[7]), nil
+	}
+}
+
+// Lookup returns the record for ip, or nil when the database has no entry for it
+func (r *Reader) Lookup(ip net.IP) (map[string]any, error) {
+	node := 0
+	addr := ip.To16()
+	if v4 := ip.To4(); v4 != nil {
+		addr = v4
+		node = r.ipv4Start
+	} else if addr == nil {
+		return nil, fmt.Errorf("invalid IP address %v", ip)
+	} else if r.Metadata.IPVersion == 4 {
+		return nil, errors.New("IPv6 lookup in an IPv4-only database")
+	}
+
+	var err error
+	for i := 0; i < len(addr)*8 && node < r.Metadata.NodeCount; i++ {
+		bit := uint(addr[i/8]>>(7-uint(i%8))) & 1
+		if node, err = r.record(node, bit); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case node == r.Metadata.NodeCount:
+		return nil, nil
+	case node < r.Metadata.NodeCount:
+		return nil, errors.New("invalid database: search tree too deep")
+	}
+
+	offset := node - r.Metadata.NodeCount - dataSectionSeparator
+	d := decoder{buf: r.data}
+	value, _, err := d.decode(offset)
+	if err != nil {
+		return nil, err
+	}
+	record, ok := value.(map[string]any)
+	if !ok {
+		return nil, errors.New("invalid database: record is not a map")
+	}
+	return record, nil
+}
+
+func toUint(v any) uint64 {
+	switch n := v.(type) {
+	case uint64:
+		return n
+	case int32:
+		return uint64(n)
+	}
+	return 0
+}
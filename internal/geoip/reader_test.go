@@ -0,0 +1,208 @@
+package geoip
+
+import (
+	"bytes"
+	"net"
+	"sort"
+	"testing"
+)
+
+// testDB builds a small IPv6 MaxMind DB with 24-bit records
+type testDB struct {
+	root *trieNode
+	data bytes.Buffer
+}
+
+type trieNode struct {
+	child  [2]*trieNode
+	offset int // data offset for leaves, -1 otherwise
+}
+
+func newTrieNode() *trieNode { return &trieNode{offset: -1} }
+
+func encodeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte(byte(typeString<<5 | len(s)))
+	buf.WriteString(s)
+}
+
+func encodeUint(buf *bytes.Buffer, typ, size int, n uint64) {
+	buf.WriteByte(byte(typ<<5 | size))
+	for i := size - 1; i >= 0; i-- {
+		buf.WriteByte(byte(n >> (8 * i)))
+	}
+}
+
+func encodeMapHeader(buf *bytes.Buffer, n int) {
+	buf.WriteByte(byte(typeMap<<5 | n))
+}
+
+func encodePointer(buf *bytes.Buffer, offset int) {
+	buf.WriteByte(byte(typePointer<<5 | offset>>8))
+	buf.WriteByte(byte(offset))
+}
+
+// encodeCountry writes {"iso_code": code, "names": {"en": name}}
+func encodeCountry(buf *bytes.Buffer, code, name string) {
+	encodeMapHeader(buf, 2)
+	encodeString(buf, "iso_code")
+	encodeString(buf, code)
+	encodeString(buf, "names")
+	encodeMapHeader(buf, 1)
+	encodeString(buf, "en")
+	encodeString(buf, name)
+}
+
+func (db *testDB) insert(cidr string, offset int) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	ones, _ := network.Mask.Size()
+	addr := network.IP.To16()
+	if network.IP.To4() != nil {
+		// IPv4 networks live under ::/96, not the ::ffff:0:0/96 mapping To16 uses
+		addr = append(make(net.IP, 12), network.IP.To4()...)
+		ones += 96
+	}
+	if db.root == nil {
+		db.root = newTrieNode()
+	}
+	node := db.root
+	for i := 0; i < ones; i++ {
+		bit := addr[i/8] >> (7 - uint(i%8)) & 1
+		if node.child[bit] == nil {
+			node.child[bit] = newTrieNode()
+		}
+		node = node.child[bit]
+	}
+	node.offset = offset
+}
+
+func (db *testDB) bytes() []byte {
+	// Number the internal nodes breadth first
+	var nodes []*trieNode
+	index := map[*trieNode]int{}
+	queue := []*trieNode{db.root}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if n.offset >= 0 {
+			continue
+		}
+		index[n] = len(nodes)
+		nodes = append(nodes, n)
+		for _, c := range n.child {
+			if c != nil {
+				queue = append(queue, c)
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	count := len(nodes)
+	for _, n := range nodes {
+		for _, c := range n.child {
+			value := count
+			switch {
+			case c == nil:
+			case c.offset >= 0:
+				value = count + dataSectionSeparator + c.offset
+			default:
+				value = index[c]
+			}
+			out.Write([]byte{byte(value >> 16), byte(value >> 8), byte(value)})
+		}
+	}
+	out.Write(make([]byte, dataSectionSeparator))
+	out.Write(db.data.Bytes())
+	out.Write(metadataMarker)
+
+	fields := map[string]func(){
+		"node_count":    func() { encodeUint(&out, typeUint32, 4, uint64(count)) },
+		"record_size":   func() { encodeUint(&out, typeUint16, 2, 24) },
+		"ip_version":    func() { encodeUint(&out, typeUint16, 2, 6) },
+		"database_type": func() { encodeString(&out, "Test-City") },
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	encodeMapHeader(&out, len(fields))
+	for _, k := range keys {
+		encodeString(&out, k)
+		fields[k]()
+	}
+	return out.Bytes()
+}
+
+func TestLocate(t *testing.T) {
+	var db testDB
+
+	// A city record with a country and region
+	city := db.data.Len()
+	encodeMapHeader(&db.data, 2)
+	encodeString(&db.data, "country")
+	germany := db.data.Len()
+	encodeCountry(&db.data, "DE", "Germany")
+	encodeString(&db.data, "subdivisions")
+	db.data.WriteByte(byte(typeExtended<<5 | 1))
+	db.data.WriteByte(byte(typeArray - 7))
+	encodeMapHeader(&db.data, 1)
+	encodeString(&db.data, "names")
+	encodeMapHeader(&db.data, 1)
+	encodeString(&db.data, "en")
+	encodeString(&db.data, "Bavaria")
+
+	// Only a registered country, shared with the city record through a pointer
+	registered := db.data.Len()
+	encodeMapHeader(&db.data, 1)
+	encodeString(&db.data, "registered_country")
+	encodePointer(&db.data, germany)
+
+	france := db.data.Len()
+	encodeMapHeader(&db.data, 1)
+	encodeString(&db.data, "country")
+	encodeCountry(&db.data, "FR", "France")
+
+	db.insert("203.0.113.0/24", city)
+	db.insert("198.51.100.0/24", registered)
+	db.insert("2001:db8::/32", france)
+
+	reader, err := FromBytes(db.bytes())
+	if err != nil {
+		t.Fatalf("FromBytes: %v", err)
+	}
+	if reader.Metadata.DatabaseType != "Test-City" {
+		t.Errorf("database type = %q", reader.Metadata.DatabaseType)
+	}
+
+	tests := []struct {
+		ip          string
+		want        Location
+		description string
+	}{
+		{"203.0.113.5", Location{"DE", "Germany", "Bavaria"}, "City record"},
+		{"198.51.100.7", Location{"DE", "Germany", ""}, "Registered country through a pointer"},
+		{"2001:db8::1", Location{"FR", "France", ""}, "IPv6 address"},
+		{"192.0.2.1", Location{}, "Address not in the database"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := reader.Locate(net.ParseIP(tt.ip))
+			if err != nil {
+				t.Fatalf("Locate: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Locate(%s) = %+v, want %+v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromBytesRejectsOtherFiles(t *testing.T) {
+	if _, err := FromBytes([]byte("not a database")); err == nil {
+		t.Error("expected an error for a file without metadata")
+	}
+}
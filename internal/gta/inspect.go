@@ -0,0 +1,288 @@
+package gta
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	ExecutableName = "gta_sa.exe"
+	SteamExeName   = "gta-sa.exe"
+	SAMPClientDLL  = "samp.dll"
+	OMPClientDLL   = "omp-client.dll"
+
+	// SupportedVersion is the only gta_sa.exe build SA-MP and open.mp run on
+	SupportedVersion = "1.0 US"
+)
+
+// knownHashes identifies gta_sa.exe builds by the SHA-256 of the whole file.
+// Only add hashes taken from a verified copy of a build.
+var knownHashes = map[string]string{}
+
+// knownSizes is the fallback for executables without a known hash. The size
+// differs between every retail, patched and Steam release, but a patched or
+// cracked copy can keep it, so a size match is reported as unverified.
+var knownSizes = map[int64]string{
+	14383616: "1.0 US",
+	14405632: "1.01",
+	5697536:  "Steam 3.0",
+}
+
+// Executable describes the game executable found in the installation
+type Executable struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	SHA256  string `json:"sha256"`
+	Version string `json:"version"`
+	// Verified reports whether Version comes from a known hash rather than the size
+	Verified bool `json:"verified"`
+}
+
+// Supported reports whether the executable is the 1.0 US build
+func (e Executable) Supported() bool {
+	return e.Version == SupportedVersion
+}
+
+// Label returns the version, marked when it was only guessed from the size
+func (e Executable) Label() string {
+	if e.Verified || e.Version == "unknown" {
+		return e.Version
+	}
+	return e.Version + " (unverified)"
+}
+
+// Component describes a multiplayer client DLL found in the installation
+type Component struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+}
+
+// Mod is an installed modification known to break SA-MP or open.mp
+type Mod struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Reason string `json:"reason"`
+}
+
+// Installation is the result of inspecting a GTA San Andreas directory
+type Installation struct {
+	Path       string      `json:"path"`
+	Executable *Executable `json:"executable,omitempty"`
+	SAMP       *Component  `json:"samp,omitempty"`
+	OpenMP     *Component  `json:"openmp,omitempty"`
+	Mods       []Mod       `json:"incompatible_mods,omitempty"`
+	// ModWarnings are mods that many servers allow but some block
+	ModWarnings []Mod `json:"mod_warnings,omitempty"`
+}
+
+// incompatibleMods lists files (relative to the GTA directory, lower case)
+// that are commonly reported to crash the client or trip server anticheats
+var incompatibleMods = map[string]string{
+	"sampfuncs.asi":                    "SAMPFUNCS is treated as a cheat by most servers",
+	"imvehft.asi":                      "ImVehFt crashes the SA-MP client when vehicles stream in",
+	"fastman92limitadjuster_gtasa.asi": "limit adjusters conflict with SA-MP memory patches",
+	"$fastman92limitadjuster.asi":      "limit adjusters conflict with SA-MP memory patches",
+}
+
+// modWarnings lists mods that are worth knowing about but not incompatible
+var modWarnings = map[string]string{
+	"cleo.asi": "CLEO is allowed on many servers, but some anticheats reject it",
+}
+
+var sampVersionPattern = regexp.MustCompile(`0\.3\.(?:7|DL)-R\d+(?:-\d+)?`)
+
+// Inspect examines the GTA San Andreas installation at path
+func Inspect(path string) (Installation, error) {
+	inst := Installation{Path: path}
+	if path == "" {
+		return inst, errors.New("GTA path is empty")
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return inst, err
+	}
+	if !info.IsDir() {
+		return inst, fmt.Errorf("%s is not a directory", path)
+	}
+
+	exePath := filepath.Join(path, ExecutableName)
+	if exe, err := inspectExecutable(exePath); err == nil {
+		inst.Executable = &exe
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return inst, err
+	}
+
+	if c, ok := inspectComponent(path, SAMPClientDLL); ok {
+		inst.SAMP = &c
+	}
+	if c, ok := inspectComponent(path, OMPClientDLL); ok {
+		inst.OpenMP = &c
+	}
+
+	inst.Mods = findMods(path, incompatibleMods)
+	inst.ModWarnings = findMods(path, modWarnings)
+	return inst, nil
+}
+
+// Warnings returns human readable problems with the installation. ModWarnings
+// are not problems and are left out.
+func (i Installation) Warnings() []string {
+	var warnings []string
+	if i.Executable == nil {
+		if _, err := os.Stat(filepath.Join(i.Path, SteamExeName)); err == nil {
+			warnings = append(warnings, "only the Steam gta-sa.exe was found; SA-MP and open.mp need a 1.0 US gta_sa.exe")
+		} else {
+			warnings = append(warnings, "gta_sa.exe not found")
+		}
+	} else if !i.Executable.Supported() {
+		warnings = append(warnings, fmt.Sprintf("gta_sa.exe is %s; SA-MP and open.mp need the %s build", i.Executable.Version, SupportedVersion))
+	}
+	if i.SAMP == nil && i.OpenMP == nil {
+		warnings = append(warnings, "no SA-MP (samp.dll) or open.mp (omp-client.dll) client installed in the GTA directory")
+	}
+	for _, mod := range i.Mods {
+		warnings = append(warnings, fmt.Sprintf("%s: %s", mod.Name, mod.Reason))
+	}
+	return warnings
+}
+
+// Summary returns a one line description of the installation
+func (i Installation) Summary() string {
+	parts := make([]string, 0, 3)
+	if i.Executable != nil {
+		parts = append(parts, "gta_sa.exe "+i.Executable.Label())
+	} else {
+		parts = append(parts, "gta_sa.exe missing")
+	}
+	if i.SAMP != nil {
+		parts = append(parts, "SA-MP "+versionOrUnknown(i.SAMP.Version))
+	}
+	if i.OpenMP != nil {
+		parts = append(parts, "open.mp "+versionOrUnknown(i.OpenMP.Version))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func versionOrUnknown(version string) string {
+	if version == "" {
+		return "(unknown version)"
+	}
+	return version
+}
+
+// inspectExecutable identifies the gta_sa.exe build from its hash, falling
+// back to its size
+func inspectExecutable(path string) (Executable, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Executable{}, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return Executable{}, err
+	}
+	if info.IsDir() {
+		return Executable{}, fmt.Errorf("%s is a directory", path)
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return Executable{}, err
+	}
+	exe := Executable{Path: path, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}
+	if version, ok := knownHashes[exe.SHA256]; ok {
+		exe.Version = version
+		exe.Verified = true
+	} else if version, ok := knownSizes[size]; ok {
+		exe.Version = version
+	} else {
+		exe.Version = "unknown"
+	}
+	return exe, nil
+}
+
+func inspectComponent(dir, name string) (Component, bool) {
+	path := filepath.Join(dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Component{}, false
+	}
+	version := fileVersion(data)
+	if name == SAMPClientDLL {
+		// samp.dll embeds its release name, which is more precise than the PE version
+		if match := sampVersionPattern.Find(data); match != nil {
+			version = string(match)
+		}
+	}
+	return Component{Name: name, Path: path, Version: version}, true
+}
+
+// fileVersion extracts the FileVersion string from a PE version resource
+func fileVersion(data []byte) string {
+	key := utf16Bytes("FileVersion\x00")
+	idx := bytes.Index(data, key)
+	if idx < 0 {
+		return ""
+	}
+	idx += len(key)
+	// The value is DWORD aligned after the key
+	for idx < len(data)-1 && data[idx] == 0 && data[idx+1] == 0 {
+		idx += 2
+	}
+	var chars []uint16
+	for i := idx; i+1 < len(data) && len(chars) < 64; i += 2 {
+		c := uint16(data[i]) | uint16(data[i+1])<<8
+		if c == 0 {
+			break
+		}
+		chars = append(chars, c)
+	}
+	return strings.TrimSpace(string(utf16.Decode(chars)))
+}
+
+func utf16Bytes(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+	out := make([]byte, 0, len(encoded)*2)
+	for _, c := range encoded {
+		out = append(out, byte(c), byte(c>>8))
+	}
+	return out
+}
+
+// findMods returns the files in dir and dir/scripts listed in known
+func findMods(dir string, known map[string]string) []Mod {
+	var mods []Mod
+	for _, sub := range []string{"", "scripts"} {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			reason, ok := known[strings.ToLower(entry.Name())]
+			if !ok {
+				continue
+			}
+			mods = append(mods, Mod{
+				Name:   entry.Name(),
+				Path:   filepath.Join(dir, sub, entry.Name()),
+				Reason: reason,
+			})
+		}
+	}
+	return mods
+}
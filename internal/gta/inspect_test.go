@@ -0,0 +1,156 @@
+package gta
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+)
+
+const supportedSize = 14383616
+
+// writeFile creates path with content, extended to size bytes when size is set
+func writeFile(t *testing.T, path string, content []byte, size int64) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if size > 0 {
+		if err := os.Truncate(path, size); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// versionResource returns the FileVersion entry of a PE version resource
+func versionResource(version string) []byte {
+	data := []byte("MZ padding ")
+	for _, c := range utf16.Encode([]rune("FileVersion\x00")) {
+		data = append(data, byte(c), byte(c>>8))
+	}
+	data = append(data, 0, 0)
+	for _, c := range utf16.Encode([]rune(version + "\x00")) {
+		data = append(data, byte(c), byte(c>>8))
+	}
+	return data
+}
+
+func TestInspectExecutable(t *testing.T) {
+	verified := []byte("verified build")
+	sum := sha256.Sum256(verified)
+	knownHashes[hex.EncodeToString(sum[:])] = SupportedVersion
+	t.Cleanup(func() { delete(knownHashes, hex.EncodeToString(sum[:])) })
+
+	tests := []struct {
+		content      []byte
+		size         int64
+		dir          bool
+		wantErr      bool
+		wantVersion  string
+		wantVerified bool
+		wantLabel    string
+		description  string
+	}{
+		{verified, 0, false, false, SupportedVersion, true, "1.0 US", "Known hash"},
+		{[]byte("patched"), supportedSize, false, false, SupportedVersion, false, "1.0 US (unverified)", "Size match only"},
+		{[]byte("patched"), 5697536, false, false, "Steam 3.0", false, "Steam 3.0 (unverified)", "Other build by size"},
+		{[]byte("unknown"), 1234, false, false, "unknown", false, "unknown", "Unknown build"},
+		{nil, 0, true, true, "", false, "", "Directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ExecutableName)
+			if tt.dir {
+				if err := os.Mkdir(path, 0o755); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				writeFile(t, path, tt.content, tt.size)
+			}
+
+			exe, err := inspectExecutable(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("inspectExecutable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if exe.Version != tt.wantVersion || exe.Verified != tt.wantVerified {
+				t.Errorf("version = %q, verified %v, want %q, %v", exe.Version, exe.Verified, tt.wantVersion, tt.wantVerified)
+			}
+			if got := exe.Label(); got != tt.wantLabel {
+				t.Errorf("Label() = %q, want %q", got, tt.wantLabel)
+			}
+			if len(exe.SHA256) != 64 {
+				t.Errorf("SHA256 = %q, want a hex digest", exe.SHA256)
+			}
+		})
+	}
+}
+
+func TestInspectExecutableMissing(t *testing.T) {
+	if _, err := inspectExecutable(filepath.Join(t.TempDir(), ExecutableName)); !os.IsNotExist(err) {
+		t.Errorf("inspectExecutable() error = %v, want not exist", err)
+	}
+}
+
+func TestInspectComponent(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     []byte
+		wantFound   bool
+		wantVersion string
+		description string
+	}{
+		{SAMPClientDLL, append(versionResource("0.3.7.2"), []byte(" SA-MP 0.3.7-R5-1 ")...), true, "0.3.7-R5-1", "SA-MP release name beats the PE version"},
+		{SAMPClientDLL, versionResource("0.3.7.2"), true, "0.3.7.2", "SA-MP without a release name"},
+		{OMPClientDLL, versionResource("1.2.0.2670"), true, "1.2.0.2670", "open.mp PE version"},
+		{OMPClientDLL, []byte("no version resource"), true, "", "Unknown version"},
+		{OMPClientDLL, nil, false, "", "Missing DLL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.content != nil {
+				writeFile(t, filepath.Join(dir, tt.name), tt.content, 0)
+			}
+			c, ok := inspectComponent(dir, tt.name)
+			if ok != tt.wantFound {
+				t.Fatalf("found = %v, want %v", ok, tt.wantFound)
+			}
+			if c.Version != tt.wantVersion {
+				t.Errorf("version = %q, want %q", c.Version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestInspectMods(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ExecutableName), []byte("exe"), supportedSize)
+	writeFile(t, filepath.Join(dir, SAMPClientDLL), []byte("0.3.7-R5-1"), 0)
+	writeFile(t, filepath.Join(dir, "SAMPFUNCS.asi"), []byte("x"), 0)
+	writeFile(t, filepath.Join(dir, "scripts", "CLEO.asi"), []byte("x"), 0)
+
+	inst, err := Inspect(dir)
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+	if len(inst.Mods) != 1 || inst.Mods[0].Name != "SAMPFUNCS.asi" {
+		t.Errorf("Mods = %+v, want only SAMPFUNCS", inst.Mods)
+	}
+	if len(inst.ModWarnings) != 1 || inst.ModWarnings[0].Name != "CLEO.asi" {
+		t.Errorf("ModWarnings = %+v, want CLEO", inst.ModWarnings)
+	}
+	// CLEO is allowed on many servers, so only SAMPFUNCS is a problem
+	if warnings := inst.Warnings(); len(warnings) != 1 {
+		t.Errorf("Warnings() = %q, want only SAMPFUNCS", warnings)
+	}
+}
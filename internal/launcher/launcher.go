@@ -3,13 +3,16 @@ package launcher
 import (
 	"errors"
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/modset"
 )
 
 type LaunchOptions struct {
@@ -18,6 +21,13 @@ type LaunchOptions struct {
 	Nickname string
 	GTAPath  string
 	Password string
+	// Name is only recorded in the launch history
+	Name string
+	// Wait keeps the launched process supervised until it exits so the
+	// session duration can be recorded
+	Wait bool
+	// ModSet names a mod set overlaid into GTAPath for this launch
+	ModSet string
 }
 
 func Launch(cfg config.Config, opts LaunchOptions) error {
@@ -26,6 +36,23 @@ func Launch(cfg config.Config, opts LaunchOptions) error {
 		return err
 	}
 
+	modsApplied, err := prepareModSet(opts)
+	if err != nil {
+		return err
+	}
+	err = launch(runtimeChoice, cfg, opts)
+	if modsApplied {
+		if err != nil {
+			restoreModSet(opts.ModSet)
+		} else {
+			// The launcher process may exit long before the game does
+			restoreAfterGame(opts.ModSet)
+		}
+	}
+	return err
+}
+
+func launch(runtimeChoice config.Runtime, cfg config.Config, opts LaunchOptions) error {
 	// For CrossOver, use CrossOverLauncher if specified, otherwise fall back to OMPLauncher
 	if runtimeChoice == config.RuntimeCrossOver {
 		if cfg.CrossOverLauncher == "" {
@@ -39,7 +66,7 @@ func Launch(cfg config.Config, opts LaunchOptions) error {
 	}
 
 	// Resolve launcher executable path
-	launcherPath := resolveLauncherPath(cfg.OMPLauncher)
+	launcherPath := ResolveLauncherPath(cfg.OMPLauncher)
 	if launcherPath == "" {
 		return errors.New("unable to find Open.MP launcher executable")
 	}
@@ -61,10 +88,59 @@ func Launch(cfg config.Config, opts LaunchOptions) error {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
+	return startAndRecord(cmd, runtimeChoice, cfg, opts)
+}
+
+// startAndRecord starts cmd and records the launch in the history file.
+// When opts.Wait is set it waits for the process and records the session duration.
+func startAndRecord(cmd *exec.Cmd, runtimeChoice config.Runtime, cfg config.Config, opts LaunchOptions) error {
 	if err := cmd.Start(); err != nil {
 		return err
 	}
-	return cmd.Process.Release()
+
+	started := time.Now()
+	entry := config.HistoryEntry{
+		Timestamp: started,
+		Name:      opts.Name,
+		Host:      opts.Host,
+		Port:      opts.Port,
+		Nickname:  opts.Nickname,
+		Runtime:   runtimeChoice,
+	}
+	if err := config.AddHistoryEntry(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record launch history: %v\n", err)
+	}
+	if err := config.SetLastPlayed(opts.Host, opts.Port, started); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record last played time: %v\n", err)
+	}
+
+	if !opts.Wait {
+		return cmd.Process.Release()
+	}
+
+	fmt.Println("Waiting for the game to exit...")
+
+	waitErr := cmd.Wait()
+	if err := config.SetHistoryDuration(started, time.Since(started)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record session duration: %v\n", err)
+	}
+	return waitErr
+}
+
+func applyModSet(opts LaunchOptions) error {
+	set, err := config.FindModSet(opts.ModSet)
+	if err != nil {
+		return err
+	}
+	plan, err := modset.BuildPlan(set, opts.GTAPath)
+	if err != nil {
+		return err
+	}
+	if err := modset.Apply(plan); err != nil {
+		return err
+	}
+	fmt.Printf("Applied mod set %q (%d file(s), %s)\n", set.Name, len(plan.Operations), plan.Mode)
+	return nil
 }
 
 func buildCommand(runtimeChoice config.Runtime, cfg config.Config, clientPath string, args []string) (*exec.Cmd, error) {
@@ -86,7 +162,10 @@ func buildCommand(runtimeChoice config.Runtime, cfg config.Config, clientPath st
 	}
 }
 
-func resolveLauncherPath(ompLauncher string) string {
+// ResolveLauncherPath returns the launcher executable for a configured path,
+// which may point at the executable itself or at its install directory.
+// It returns an empty string when no executable can be found.
+func ResolveLauncherPath(ompLauncher string) string {
 	if ompLauncher == "" {
 		return ""
 	}
@@ -147,7 +226,7 @@ func launchViaCrossOver(cfg config.Config, opts LaunchOptions) error {
 	}
 
 	// Build command: wine omp-launcher-tui.exe connect -h <host> -p <port> -n <nickname>
-	cmdArgs := []string{cfg.CrossOverLauncher, "connect", "-nickname", opts.Nickname, fmt.Sprintf("%s:%d", opts.Host, opts.Port)}
+	cmdArgs := []string{cfg.CrossOverLauncher, "connect", "-nickname", opts.Nickname, net.JoinHostPort(opts.Host, itoa(opts.Port))}
 
 	cmd := exec.Command(winePath, cmdArgs...)
 
@@ -169,8 +248,5 @@ func launchViaCrossOver(cfg config.Config, opts LaunchOptions) error {
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
 
-	if err := cmd.Start(); err != nil {
-		return err
-	}
-	return cmd.Process.Release()
+	return startAndRecord(cmd, config.RuntimeCrossOver, cfg, opts)
 }
@@ -0,0 +1,123 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/modset"
+)
+
+// gameExecutable is the game process a mod set stays applied for
+const gameExecutable = "gta_sa.exe"
+
+const (
+	// gameStartTimeout is how long the launcher may take to start the game
+	gameStartTimeout = 2 * time.Minute
+	gamePollInterval = 2 * time.Second
+)
+
+// GameRunning reports whether GTA San Andreas is running, natively or under
+// Wine, Proton or CrossOver
+func GameRunning() (bool, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("tasklist", "/FO", "CSV", "/NH")
+	} else {
+		cmd = exec.Command("ps", "-A", "-o", "command=")
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to list processes: %w", err)
+	}
+	return strings.Contains(strings.ToLower(string(out)), gameExecutable), nil
+}
+
+// prepareModSet applies opts.ModSet and reports whether it was applied for this
+// launch. A set left over from an earlier session is only restored once its
+// game has exited; while the game runs its files are left alone.
+func prepareModSet(opts LaunchOptions) (bool, error) {
+	applied := modset.Applied()
+	running, err := GameRunning()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	if applied != "" {
+		switch {
+		case err == nil && !running:
+			// The earlier session is over, e.g. after a crash
+			if err := modset.Restore(); err != nil {
+				return false, fmt.Errorf("failed to restore previous mod set: %w", err)
+			}
+			fmt.Printf("Restored vanilla GTA directory (mod set %q from an earlier session removed)\n", applied)
+		case applied == opts.ModSet:
+			// Still in use and the set wanted anyway; the earlier session restores it
+			return false, nil
+		default:
+			return false, fmt.Errorf("mod set %q is still applied for a running game; close GTA or run 'modset restore' first", applied)
+		}
+	}
+
+	if opts.ModSet == "" {
+		return false, nil
+	}
+	if running {
+		return false, fmt.Errorf("GTA is already running; close it before applying mod set %q", opts.ModSet)
+	}
+	if err := applyModSet(opts); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// restoreAfterGame waits for the game to start and exit, then restores the
+// vanilla GTA directory. If the game cannot be followed the set stays applied
+// until 'modset restore' or the next launch.
+func restoreAfterGame(name string) {
+	fmt.Printf("Mod set %q stays applied until GTA exits...\n", name)
+	if err := waitForGameExit(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; mod set %q stays applied. Run 'modset restore' after closing the game.\n", err, name)
+		return
+	}
+	restoreModSet(name)
+}
+
+// waitForGameExit blocks until the game process has appeared and exited again
+func waitForGameExit() error {
+	deadline := time.Now().Add(gameStartTimeout)
+	for {
+		running, err := GameRunning()
+		if err != nil {
+			return err
+		}
+		if running {
+			break
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s did not start within %s", gameExecutable, gameStartTimeout)
+		}
+		time.Sleep(gamePollInterval)
+	}
+	for {
+		time.Sleep(gamePollInterval)
+		running, err := GameRunning()
+		if err != nil {
+			return err
+		}
+		if !running {
+			return nil
+		}
+	}
+}
+
+func restoreModSet(name string) {
+	if err := modset.Restore(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to restore mod set %q: %v\n", name, err)
+		return
+	}
+	fmt.Printf("Restored vanilla GTA directory (mod set %q removed)\n", name)
+}
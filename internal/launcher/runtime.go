@@ -48,3 +48,44 @@ func isCrossOverInstalled() bool {
 	}
 	return false
 }
+
+// RuntimeCandidate describes one runtime DetectRuntime considers in auto mode.
+type RuntimeCandidate struct {
+	Runtime   config.Runtime
+	Path      string
+	Available bool
+	Reason    string
+}
+
+// RuntimeCandidates reports every runtime DetectRuntime checks on this
+// platform, in the same order, along with whether it is usable.
+func RuntimeCandidates() []RuntimeCandidate {
+	candidates := []RuntimeCandidate{lookPathCandidate(config.RuntimeProton, "proton")}
+	if runtime.GOOS == "darwin" {
+		crossOver := RuntimeCandidate{Runtime: config.RuntimeCrossOver}
+		if isCrossOverInstalled() {
+			crossOver.Path = "/Applications/CrossOver.app/Contents/SharedSupport/CrossOver/bin/wine"
+			crossOver.Available = true
+		} else {
+			crossOver.Reason = "CrossOver.app is not installed"
+		}
+		candidates = append(candidates, crossOver)
+	}
+	candidates = append(candidates, lookPathCandidate(config.RuntimeWine, "wine"))
+	if runtime.GOOS == "windows" {
+		candidates = append(candidates, RuntimeCandidate{Runtime: config.RuntimeNative, Available: true})
+	}
+	return candidates
+}
+
+func lookPathCandidate(rt config.Runtime, binary string) RuntimeCandidate {
+	candidate := RuntimeCandidate{Runtime: rt}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		candidate.Reason = binary + " not found in PATH"
+		return candidate
+	}
+	candidate.Path = path
+	candidate.Available = true
+	return candidate
+}
@@ -0,0 +1,404 @@
+package modset
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+)
+
+const (
+	ManifestFile = "modset_manifest.json"
+	BackupDir    = "modset_backup"
+)
+
+// Operation overlays a single file from the mod set into the GTA directory
+type Operation struct {
+	Rel    string `json:"rel"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	// Replaces is true when the target already exists and is backed up first
+	Replaces bool `json:"replaces"`
+}
+
+// Plan lists everything Apply will do without touching the GTA directory
+type Plan struct {
+	Set        config.ModSet     `json:"set"`
+	GTAPath    string            `json:"gta_path"`
+	Mode       config.ModSetMode `json:"mode"`
+	Operations []Operation       `json:"operations"`
+	// Conflicts block Apply, e.g. a file in the set where GTA has a directory,
+	// or a file provided by two sources of the set
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+// ManifestEntry records how to undo one overlaid file
+type ManifestEntry struct {
+	Target string `json:"target"`
+	Backup string `json:"backup,omitempty"`
+}
+
+// Manifest records an applied mod set so it can be restored, even after a crash
+type Manifest struct {
+	Set         string          `json:"set"`
+	GTAPath     string          `json:"gta_path"`
+	Mode        string          `json:"mode"`
+	AppliedAt   time.Time       `json:"applied_at"`
+	Entries     []ManifestEntry `json:"entries"`
+	CreatedDirs []string        `json:"created_dirs,omitempty"`
+}
+
+// ManifestPath returns the path to the applied mod set manifest
+func ManifestPath() (string, error) {
+	dir, err := config.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, ManifestFile), nil
+}
+
+// BuildPlan walks the mod set sources and works out what Apply would change in gtaPath
+func BuildPlan(set config.ModSet, gtaPath string) (Plan, error) {
+	plan := Plan{Set: set, GTAPath: gtaPath, Mode: resolveMode(set.Mode)}
+	if gtaPath == "" {
+		return plan, errors.New("GTA path is not configured")
+	}
+	sources := set.SourceDirs()
+	if len(sources) == 0 {
+		return plan, fmt.Errorf("mod set %q has no source", set.Name)
+	}
+
+	// files and dirs map the paths seen so far to the source providing them
+	files := make(map[string]string)
+	dirs := make(map[string]string)
+	for _, source := range sources {
+		if err := plan.walkSource(source, files, dirs); err != nil {
+			return plan, err
+		}
+	}
+
+	sort.Slice(plan.Operations, func(i, j int) bool {
+		return plan.Operations[i].Rel < plan.Operations[j].Rel
+	})
+	return plan, nil
+}
+
+// walkSource adds the files of one source directory to the plan. A path that
+// another source already provides is a conflict.
+func (p *Plan) walkSource(source string, files, dirs map[string]string) error {
+	info, err := os.Stat(source)
+	if err != nil {
+		return fmt.Errorf("mod set %q source: %w", p.Set.Name, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("mod set %q source %s is not a directory", p.Set.Name, source)
+	}
+
+	return filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		target := filepath.Join(p.GTAPath, rel)
+		existing, statErr := os.Lstat(target)
+
+		if d.IsDir() {
+			if other, ok := files[rel]; ok {
+				p.Conflicts = append(p.Conflicts, fmt.Sprintf("%s is a directory in %s but a file in %s", rel, source, other))
+				return fs.SkipDir
+			}
+			if statErr == nil && !existing.IsDir() {
+				p.Conflicts = append(p.Conflicts, fmt.Sprintf("%s is a directory in the mod set but a file in GTA", rel))
+				return fs.SkipDir
+			}
+			dirs[rel] = source
+			return nil
+		}
+
+		if other, ok := files[rel]; ok {
+			p.Conflicts = append(p.Conflicts, fmt.Sprintf("%s is in both %s and %s", rel, other, source))
+			return nil
+		}
+		if other, ok := dirs[rel]; ok {
+			p.Conflicts = append(p.Conflicts, fmt.Sprintf("%s is a file in %s but a directory in %s", rel, source, other))
+			return nil
+		}
+		files[rel] = source
+
+		op := Operation{Rel: rel, Source: path, Target: target}
+		if statErr == nil {
+			if existing.IsDir() {
+				p.Conflicts = append(p.Conflicts, fmt.Sprintf("%s is a file in the mod set but a directory in GTA", rel))
+				return nil
+			}
+			op.Replaces = true
+		}
+		p.Operations = append(p.Operations, op)
+		return nil
+	})
+}
+
+// String renders the plan as a dry-run listing
+func (p Plan) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Mod set %q (%s) -> %s\n", p.Set.Name, p.Mode, p.GTAPath)
+	if len(p.Operations) == 0 {
+		b.WriteString("  (no files)\n")
+	}
+	for _, op := range p.Operations {
+		action := "add    "
+		if op.Replaces {
+			action = "replace"
+		}
+		fmt.Fprintf(&b, "  %s %s\n", action, op.Rel)
+	}
+	for _, conflict := range p.Conflicts {
+		fmt.Fprintf(&b, "  CONFLICT %s\n", conflict)
+	}
+	return b.String()
+}
+
+// Apply overlays the plan into the GTA directory and writes the manifest.
+// Any mod set still applied from an earlier launch is restored first.
+func Apply(plan Plan) error {
+	if len(plan.Conflicts) > 0 {
+		return fmt.Errorf("mod set %q has %d conflict(s): %s", plan.Set.Name, len(plan.Conflicts), strings.Join(plan.Conflicts, "; "))
+	}
+	if err := Restore(); err != nil {
+		return fmt.Errorf("failed to restore previous mod set: %w", err)
+	}
+
+	configDir, err := config.ConfigDir()
+	if err != nil {
+		return err
+	}
+	backupRoot := filepath.Join(configDir, BackupDir)
+	if err := os.RemoveAll(backupRoot); err != nil {
+		return err
+	}
+
+	manifest := Manifest{
+		Set:       plan.Set.Name,
+		GTAPath:   plan.GTAPath,
+		Mode:      string(plan.Mode),
+		AppliedAt: time.Now(),
+	}
+
+	for _, op := range plan.Operations {
+		created, err := mkdirAllTracked(filepath.Dir(op.Target))
+		manifest.CreatedDirs = append(manifest.CreatedDirs, created...)
+		if err != nil {
+			return rollback(manifest, err)
+		}
+
+		entry := ManifestEntry{Target: op.Target}
+		if op.Replaces {
+			entry.Backup = filepath.Join(backupRoot, op.Rel)
+		}
+		// Record before touching the original so a failure or crash can always
+		// restore it; undo leaves a target alone while its backup is missing
+		manifest.Entries = append(manifest.Entries, entry)
+		if err := saveManifest(manifest); err != nil {
+			return rollback(manifest, err)
+		}
+		if op.Replaces {
+			if err := os.MkdirAll(filepath.Dir(entry.Backup), config.DefaultPerms); err != nil {
+				return rollback(manifest, err)
+			}
+			if err := moveFile(op.Target, entry.Backup); err != nil {
+				return rollback(manifest, err)
+			}
+		}
+
+		if plan.Mode == config.ModSetModeSymlink {
+			err = os.Symlink(op.Source, op.Target)
+		} else {
+			err = copyFile(op.Source, op.Target)
+		}
+		if err != nil {
+			return rollback(manifest, err)
+		}
+	}
+
+	return saveManifest(manifest)
+}
+
+// Restore undoes the currently applied mod set, if any, returning GTA to its vanilla state
+func Restore() error {
+	manifest, err := loadManifest()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if err := undo(manifest); err != nil {
+		return err
+	}
+	path, err := ManifestPath()
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Applied returns the name of the currently applied mod set, or an empty string
+func Applied() string {
+	manifest, err := loadManifest()
+	if err != nil {
+		return ""
+	}
+	return manifest.Set
+}
+
+func rollback(manifest Manifest, cause error) error {
+	if err := undo(manifest); err != nil {
+		return fmt.Errorf("%w (rollback also failed: %v)", cause, err)
+	}
+	if path, err := ManifestPath(); err == nil {
+		_ = os.Remove(path)
+	}
+	return cause
+}
+
+func undo(manifest Manifest) error {
+	var errs []error
+	for i := len(manifest.Entries) - 1; i >= 0; i-- {
+		entry := manifest.Entries[i]
+		if entry.Backup != "" {
+			if _, err := os.Lstat(entry.Backup); errors.Is(err, os.ErrNotExist) {
+				// The original was never moved and is still in place
+				continue
+			}
+		}
+		if err := os.Remove(entry.Target); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		if entry.Backup != "" {
+			if err := moveFile(entry.Backup, entry.Target); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	// Remove directories created for the overlay, deepest first
+	for i := len(manifest.CreatedDirs) - 1; i >= 0; i-- {
+		_ = os.Remove(manifest.CreatedDirs[i])
+	}
+	return errors.Join(errs...)
+}
+
+func resolveMode(mode config.ModSetMode) config.ModSetMode {
+	if mode == config.ModSetModeSymlink || mode == config.ModSetModeCopy {
+		return mode
+	}
+	// Symlinks need elevated privileges on Windows
+	if runtime.GOOS == "windows" {
+		return config.ModSetModeCopy
+	}
+	return config.ModSetModeSymlink
+}
+
+// mkdirAllTracked creates dir and its missing parents and returns the ones it created
+func mkdirAllTracked(dir string) ([]string, error) {
+	var missing []string
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			break
+		}
+		missing = append(missing, d)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	created := make([]string, 0, len(missing))
+	for i := len(missing) - 1; i >= 0; i-- {
+		if err := os.Mkdir(missing[i], config.DefaultPerms); err != nil {
+			return created, err
+		}
+		created = append(created, missing[i])
+	}
+	return created, nil
+}
+
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	// Rename fails across filesystems; fall back to copy and delete. The copy
+	// only appears at dst once complete, so a crash never leaves half a backup.
+	tmp := dst + ".tmp"
+	if err := copyFile(src, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Remove(src)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func loadManifest() (Manifest, error) {
+	path, err := ManifestPath()
+	if err != nil {
+		return Manifest{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+func saveManifest(manifest Manifest) error {
+	path, err := ManifestPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), config.DefaultPerms); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
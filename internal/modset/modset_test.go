@@ -0,0 +1,282 @@
+package modset
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+)
+
+// writeFiles creates files relative to root; a name ending in / is a directory
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if strings.HasSuffix(name, "/") {
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestBuildPlan(t *testing.T) {
+	tests := []struct {
+		sources       []map[string]string
+		gta           map[string]string
+		wantOps       []string
+		wantReplaces  []string
+		wantConflicts int
+		description   string
+	}{
+		{
+			[]map[string]string{{"samp.cfg": "mod", "modloader/hud/hud.txd": "mod"}},
+			map[string]string{"gta_sa.exe": ""},
+			[]string{"modloader/hud/hud.txd", "samp.cfg"}, nil, 0,
+			"New files",
+		},
+		{
+			[]map[string]string{{"samp.cfg": "mod"}},
+			map[string]string{"samp.cfg": "vanilla"},
+			[]string{"samp.cfg"}, []string{"samp.cfg"}, 0,
+			"Existing file is replaced",
+		},
+		{
+			[]map[string]string{{"models": "mod"}},
+			map[string]string{"models/gta3.img": ""},
+			nil, nil, 1,
+			"File where GTA has a directory",
+		},
+		{
+			[]map[string]string{{"samp.cfg/": ""}},
+			map[string]string{"samp.cfg": "vanilla"},
+			nil, nil, 1,
+			"Directory where GTA has a file",
+		},
+		{
+			[]map[string]string{{"samp.cfg": "a", "a.txt": "a"}, {"samp.cfg": "b", "b.txt": "b"}},
+			nil,
+			[]string{"a.txt", "b.txt", "samp.cfg"}, nil, 1,
+			"File in two sources",
+		},
+		{
+			[]map[string]string{{"modloader/x.txt": "a"}, {"modloader": "b"}},
+			nil,
+			[]string{"modloader/x.txt"}, nil, 1,
+			"File in one source and directory in another",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			root := t.TempDir()
+			gta := filepath.Join(root, "gta")
+			writeFiles(t, gta, tt.gta)
+			if err := os.MkdirAll(gta, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			set := config.ModSet{Name: "test", Mode: config.ModSetModeCopy}
+			for i, files := range tt.sources {
+				dir := filepath.Join(root, "mod"+string(rune('a'+i)))
+				writeFiles(t, dir, files)
+				if i == 0 {
+					set.Source = dir
+				} else {
+					set.Sources = append(set.Sources, dir)
+				}
+			}
+
+			plan, err := BuildPlan(set, gta)
+			if err != nil {
+				t.Fatalf("BuildPlan() error = %v", err)
+			}
+			var ops, replaces []string
+			for _, op := range plan.Operations {
+				ops = append(ops, filepath.ToSlash(op.Rel))
+				if op.Replaces {
+					replaces = append(replaces, filepath.ToSlash(op.Rel))
+				}
+			}
+			if !slices.Equal(ops, tt.wantOps) {
+				t.Errorf("operations = %q, want %q", ops, tt.wantOps)
+			}
+			if !slices.Equal(replaces, tt.wantReplaces) {
+				t.Errorf("replaces = %q, want %q", replaces, tt.wantReplaces)
+			}
+			if len(plan.Conflicts) != tt.wantConflicts {
+				t.Errorf("conflicts = %q, want %d", plan.Conflicts, tt.wantConflicts)
+			}
+		})
+	}
+}
+
+func TestBuildPlanErrors(t *testing.T) {
+	root := t.TempDir()
+	tests := []struct {
+		set         config.ModSet
+		gtaPath     string
+		description string
+	}{
+		{config.ModSet{Name: "a", Source: root}, "", "No GTA path"},
+		{config.ModSet{Name: "a"}, root, "No source"},
+		{config.ModSet{Name: "a", Source: filepath.Join(root, "missing")}, root, "Missing source"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if _, err := BuildPlan(tt.set, tt.gtaPath); err == nil {
+				t.Error("BuildPlan() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestApplyAndRestore(t *testing.T) {
+	config.UseTempDirForTest(t)
+	root := t.TempDir()
+	gta := filepath.Join(root, "gta")
+	mod := filepath.Join(root, "mod")
+	writeFiles(t, gta, map[string]string{"samp.cfg": "vanilla", "gta_sa.exe": "exe"})
+	writeFiles(t, mod, map[string]string{"samp.cfg": "mod", "modloader/hud/hud.txd": "hud"})
+
+	plan, err := BuildPlan(config.ModSet{Name: "rp", Source: mod, Mode: config.ModSetModeCopy}, gta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply(plan); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if got := readFile(t, filepath.Join(gta, "samp.cfg")); got != "mod" {
+		t.Errorf("samp.cfg = %q after Apply, want the mod file", got)
+	}
+	if got := readFile(t, filepath.Join(gta, "modloader", "hud", "hud.txd")); got != "hud" {
+		t.Errorf("hud.txd = %q after Apply, want the mod file", got)
+	}
+	if got := Applied(); got != "rp" {
+		t.Errorf("Applied() = %q, want rp", got)
+	}
+
+	if err := Restore(); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got := readFile(t, filepath.Join(gta, "samp.cfg")); got != "vanilla" {
+		t.Errorf("samp.cfg = %q after Restore, want the original", got)
+	}
+	if _, err := os.Stat(filepath.Join(gta, "modloader")); !os.IsNotExist(err) {
+		t.Errorf("modloader still exists after Restore: %v", err)
+	}
+	if got := Applied(); got != "" {
+		t.Errorf("Applied() = %q after Restore, want none", got)
+	}
+	if err := Restore(); err != nil {
+		t.Errorf("Restore() without a manifest error = %v", err)
+	}
+}
+
+func TestApplyRefusesConflicts(t *testing.T) {
+	config.UseTempDirForTest(t)
+	plan := Plan{Set: config.ModSet{Name: "bad"}, Conflicts: []string{"samp.cfg is in both a and b"}}
+	if err := Apply(plan); err == nil {
+		t.Fatal("Apply() error = nil, want the conflicts")
+	}
+	if got := Applied(); got != "" {
+		t.Errorf("Applied() = %q, want none", got)
+	}
+}
+
+// TestRestoreFromManifest restores the files recorded by a session that never
+// cleaned up, e.g. after a crash
+func TestRestoreFromManifest(t *testing.T) {
+	config.UseTempDirForTest(t)
+	root := t.TempDir()
+	gta := filepath.Join(root, "gta")
+	backup := filepath.Join(root, "backup", "samp.cfg")
+	writeFiles(t, gta, map[string]string{"samp.cfg": "mod", "cleo/x.cs": "mod"})
+	writeFiles(t, filepath.Dir(backup), map[string]string{"samp.cfg": "vanilla"})
+
+	manifest := Manifest{
+		Set:     "crashed",
+		GTAPath: gta,
+		Entries: []ManifestEntry{
+			{Target: filepath.Join(gta, "samp.cfg"), Backup: backup},
+			{Target: filepath.Join(gta, "cleo", "x.cs")},
+		},
+		CreatedDirs: []string{filepath.Join(gta, "cleo")},
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := ManifestPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, filepath.Dir(path), map[string]string{filepath.Base(path): string(data)})
+
+	if got := Applied(); got != "crashed" {
+		t.Fatalf("Applied() = %q, want crashed", got)
+	}
+	if err := Restore(); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got := readFile(t, filepath.Join(gta, "samp.cfg")); got != "vanilla" {
+		t.Errorf("samp.cfg = %q, want the backup", got)
+	}
+	if _, err := os.Stat(filepath.Join(gta, "cleo")); !os.IsNotExist(err) {
+		t.Errorf("cleo still exists after Restore: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("manifest still exists after Restore: %v", err)
+	}
+}
+
+// TestRestoreBeforeBackup restores a manifest saved right before a crash, when
+// the original was not moved to the backup yet
+func TestRestoreBeforeBackup(t *testing.T) {
+	config.UseTempDirForTest(t)
+	root := t.TempDir()
+	gta := filepath.Join(root, "gta")
+	writeFiles(t, gta, map[string]string{"samp.cfg": "vanilla"})
+
+	manifest := Manifest{
+		Set:     "crashed",
+		GTAPath: gta,
+		Entries: []ManifestEntry{{Target: filepath.Join(gta, "samp.cfg"), Backup: filepath.Join(root, "backup", "samp.cfg")}},
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := ManifestPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, filepath.Dir(path), map[string]string{filepath.Base(path): string(data)})
+
+	if err := Restore(); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got := readFile(t, filepath.Join(gta, "samp.cfg")); got != "vanilla" {
+		t.Errorf("samp.cfg = %q, want the original left in place", got)
+	}
+}
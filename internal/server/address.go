@@ -0,0 +1,49 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// DefaultPort is the SA-MP and open.mp default server port
+const DefaultPort = 7777
+
+// JoinAddr formats host and port as host:port, bracketing IPv6 hosts
+func JoinAddr(host string, port int) string {
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// SplitAddress splits "host", "host:port", "[v6]", "[v6]:port" or a bare IPv6
+// address into host and port. The port is empty when the address has none.
+func SplitAddress(addr string) (host, port string, err error) {
+	addr = strings.TrimSpace(addr)
+
+	if strings.HasPrefix(addr, "[") {
+		if h, p, err := net.SplitHostPort(addr); err == nil {
+			return h, p, nil
+		}
+		if strings.HasSuffix(addr, "]") {
+			return addr[1 : len(addr)-1], "", nil
+		}
+		return "", "", errors.New("invalid bracketed address. Expected '[ipv6]:port' (e.g., '[::1]:7777')")
+	}
+
+	if strings.Count(addr, ":") > 1 {
+		if net.ParseIP(addr) != nil {
+			return addr, "", nil
+		}
+		return "", "", errors.New("IPv6 addresses with a port must be bracketed (e.g., '[::1]:7777')")
+	}
+
+	if h, p, ok := strings.Cut(addr, ":"); ok {
+		return h, p, nil
+	}
+	return addr, "", nil
+}
+
+// IsIP reports whether host is a literal IPv4 or IPv6 address rather than a DNS name
+func IsIP(host string) bool {
+	return net.ParseIP(host) != nil
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// dnsTTL is how long a resolved hostname is reused before resolving it again
+	dnsTTL = 5 * time.Minute
+	// dnsFailureTTL is shorter so a fixed DNS record is picked up quickly
+	dnsFailureTTL = 30 * time.Second
+)
+
+// hostResolver looks up the addresses of a hostname, e.g. net.DefaultResolver
+type hostResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// resolver is used by ResolveHost; tests replace it
+var resolver hostResolver = net.DefaultResolver
+
+type dnsEntry struct {
+	ip      string
+	err     error
+	expires time.Time
+}
+
+var dnsCache = struct {
+	sync.Mutex
+	entries map[string]dnsEntry
+}{entries: make(map[string]dnsEntry)}
+
+// ResolveHost returns the IP address for host, caching the result for dnsTTL.
+// Literal IP addresses are returned as is. IPv4 addresses are preferred because
+// the SA-MP query protocol embeds the server address as four bytes.
+func ResolveHost(ctx context.Context, host string) (string, error) {
+	if IsIP(host) {
+		return host, nil
+	}
+
+	dnsCache.Lock()
+	entry, ok := dnsCache.entries[host]
+	dnsCache.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return entry.ip, entry.err
+	}
+
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		// Only cache real DNS answers, not our own timeouts or cancellations
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && !dnsErr.IsTimeout {
+			storeDNS(host, dnsEntry{err: err, expires: time.Now().Add(dnsFailureTTL)})
+		}
+		return "", err
+	}
+	if len(addrs) == 0 {
+		err := &net.DNSError{Err: "no addresses found", Name: host, IsNotFound: true}
+		storeDNS(host, dnsEntry{err: err, expires: time.Now().Add(dnsFailureTTL)})
+		return "", err
+	}
+
+	ip := addrs[0].IP
+	for _, addr := range addrs {
+		if addr.IP.To4() != nil {
+			ip = addr.IP
+			break
+		}
+	}
+	storeDNS(host, dnsEntry{ip: ip.String(), expires: time.Now().Add(dnsTTL)})
+	return ip.String(), nil
+}
+
+// CachedIP returns the last resolved IP for host without resolving it, or an empty string
+func CachedIP(host string) string {
+	if IsIP(host) {
+		return host
+	}
+	dnsCache.Lock()
+	defer dnsCache.Unlock()
+	return dnsCache.entries[host].ip
+}
+
+func storeDNS(host string, entry dnsEntry) {
+	dnsCache.Lock()
+	dnsCache.entries[host] = entry
+	dnsCache.Unlock()
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeResolver answers lookups from a map and counts them
+type fakeResolver struct {
+	addrs   map[string][]net.IPAddr
+	errs    map[string]error
+	lookups map[string]int
+}
+
+func (r *fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	r.lookups[host]++
+	if err := r.errs[host]; err != nil {
+		return nil, err
+	}
+	return r.addrs[host], nil
+}
+
+// useFakeResolver replaces the resolver and empties the DNS cache for the test
+func useFakeResolver(t *testing.T, r *fakeResolver) {
+	prev := resolver
+	resolver = r
+	dnsCache.Lock()
+	dnsCache.entries = make(map[string]dnsEntry)
+	dnsCache.Unlock()
+	t.Cleanup(func() {
+		resolver = prev
+		dnsCache.Lock()
+		dnsCache.entries = make(map[string]dnsEntry)
+		dnsCache.Unlock()
+	})
+}
+
+func ipAddrs(ips ...string) []net.IPAddr {
+	addrs := make([]net.IPAddr, len(ips))
+	for i, ip := range ips {
+		addrs[i] = net.IPAddr{IP: net.ParseIP(ip)}
+	}
+	return addrs
+}
+
+func TestResolveHost(t *testing.T) {
+	tests := []struct {
+		host        string
+		wantIP      string
+		wantErr     bool
+		wantLookups int
+		description string
+	}{
+		{"203.0.113.5", "203.0.113.5", false, 0, "Literal IP is not resolved"},
+		{"play.example.com", "203.0.113.5", false, 1, "Resolved once and cached"},
+		{"dual.example.com", "203.0.113.6", false, 1, "IPv4 is preferred"},
+		{"v6.example.com", "2001:db8::1", false, 1, "IPv6 only"},
+		{"missing.example.com", "", true, 1, "Not found is cached"},
+		{"empty.example.com", "", true, 1, "No addresses is cached"},
+		{"slow.example.com", "", true, 2, "Timeout is not cached"},
+		{"cancel.example.com", "", true, 2, "Cancellation is not cached"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			r := &fakeResolver{
+				addrs: map[string][]net.IPAddr{
+					"play.example.com": ipAddrs("203.0.113.5"),
+					"dual.example.com": ipAddrs("2001:db8::6", "203.0.113.6"),
+					"v6.example.com":   ipAddrs("2001:db8::1"),
+				},
+				errs: map[string]error{
+					"missing.example.com": &net.DNSError{Err: "no such host", Name: "missing.example.com", IsNotFound: true},
+					"slow.example.com":    &net.DNSError{Err: "i/o timeout", Name: "slow.example.com", IsTimeout: true},
+					"cancel.example.com":  context.Canceled,
+				},
+				lookups: map[string]int{},
+			}
+			useFakeResolver(t, r)
+
+			// The second call is answered from the cache unless the first failed transiently
+			for i := 0; i < 2; i++ {
+				ip, err := ResolveHost(context.Background(), tt.host)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("ResolveHost() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if ip != tt.wantIP {
+					t.Errorf("ResolveHost() = %q, want %q", ip, tt.wantIP)
+				}
+			}
+			if got := r.lookups[tt.host]; got != tt.wantLookups {
+				t.Errorf("lookups = %d, want %d", got, tt.wantLookups)
+			}
+			if got := CachedIP(tt.host); got != tt.wantIP {
+				t.Errorf("CachedIP() = %q, want %q", got, tt.wantIP)
+			}
+		})
+	}
+}
+
+func TestResolveHostExpired(t *testing.T) {
+	r := &fakeResolver{
+		addrs:   map[string][]net.IPAddr{"play.example.com": ipAddrs("203.0.113.7")},
+		lookups: map[string]int{},
+	}
+	useFakeResolver(t, r)
+	storeDNS("play.example.com", dnsEntry{ip: "203.0.113.5", expires: time.Now().Add(-time.Second)})
+	storeDNS("down.example.com", dnsEntry{err: errors.New("no such host"), expires: time.Now().Add(time.Minute)})
+
+	ip, err := ResolveHost(context.Background(), "play.example.com")
+	if err != nil || ip != "203.0.113.7" {
+		t.Errorf("ResolveHost() = %q, %v, want the new address", ip, err)
+	}
+	if r.lookups["play.example.com"] != 1 {
+		t.Errorf("expired entry was not resolved again")
+	}
+	if _, err := ResolveHost(context.Background(), "down.example.com"); err == nil {
+		t.Error("ResolveHost() error = nil, want the cached failure")
+	}
+	if r.lookups["down.example.com"] != 0 {
+		t.Errorf("cached failure was resolved again")
+	}
+}
+
+func TestQueryIPv6Unsupported(t *testing.T) {
+	r := &fakeResolver{
+		addrs:   map[string][]net.IPAddr{"v6.example.com": ipAddrs("2001:db8::1")},
+		lookups: map[string]int{},
+	}
+	useFakeResolver(t, r)
+
+	for _, host := range []string{"::1", "v6.example.com"} {
+		t.Run(host, func(t *testing.T) {
+			_, err := QueryServer(context.Background(), host, 7777)
+			if !errors.Is(err, ErrIPv6Unsupported) {
+				t.Errorf("QueryServer() error = %v, want %v", err, ErrIPv6Unsupported)
+			}
+			if got := ErrorStatus(err); got != StatusUnsupported {
+				t.Errorf("ErrorStatus() = %q, want %q", got, StatusUnsupported)
+			}
+		})
+	}
+}
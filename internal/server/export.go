@@ -0,0 +1,110 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Export formats, named after their file extensions
+const (
+	ExportJSON = "json"
+	ExportCSV  = "csv"
+)
+
+// ExportRecord is a server as written by ExportServers
+type ExportRecord struct {
+	Name       string   `json:"name"`
+	Address    string   `json:"address"`
+	Host       string   `json:"host"`
+	Port       int      `json:"port"`
+	Players    int      `json:"players"`
+	MaxPlayers int      `json:"max_players"`
+	PingMS     int64    `json:"ping_ms"`
+	Passworded bool     `json:"passworded"`
+	Version    string   `json:"version,omitempty"`
+	Language   string   `json:"language,omitempty"`
+	Gamemode   string   `json:"gamemode,omitempty"`
+	Country    string   `json:"country,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+var exportHeader = []string{"name", "address", "host", "port", "players", "max_players", "ping_ms", "passworded", "version", "language", "gamemode", "country", "tags"}
+
+// ExportFormat returns the format for a file name, from its extension
+func ExportFormat(path string) (string, error) {
+	switch ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")); ext {
+	case ExportJSON, ExportCSV:
+		return ext, nil
+	default:
+		return "", fmt.Errorf("unsupported export file %q (expected .json or .csv)", path)
+	}
+}
+
+func exportRecord(s Server) ExportRecord {
+	return ExportRecord{
+		Name:       s.Name,
+		Address:    s.Addr(),
+		Host:       s.Host,
+		Port:       s.Port,
+		Players:    s.Players,
+		MaxPlayers: s.MaxPlayers,
+		PingMS:     s.Ping.Milliseconds(),
+		Passworded: s.Passworded,
+		Version:    s.Version.String(),
+		Language:   s.Language,
+		Gamemode:   s.Gamemode,
+		Country:    s.Country,
+		Tags:       s.Tags,
+	}
+}
+
+// ExportServers writes servers to w as a JSON array or as CSV with a header row.
+// CSV joins tags with semicolons and escapes text that spreadsheets would run
+// as a formula.
+func ExportServers(w io.Writer, servers []Server, format string) error {
+	records := make([]ExportRecord, len(servers))
+	for i, s := range servers {
+		records[i] = exportRecord(s)
+	}
+
+	switch format {
+	case ExportJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case ExportCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(exportHeader); err != nil {
+			return err
+		}
+		for _, r := range records {
+			row := []string{
+				csvText(r.Name), r.Address, r.Host, strconv.Itoa(r.Port),
+				strconv.Itoa(r.Players), strconv.Itoa(r.MaxPlayers), strconv.FormatInt(r.PingMS, 10),
+				strconv.FormatBool(r.Passworded), csvText(r.Version), csvText(r.Language), csvText(r.Gamemode), csvText(r.Country),
+				csvText(strings.Join(r.Tags, ";")),
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+// csvText prefixes text starting like a formula with a quote, so spreadsheets
+// show server-provided names as text instead of running them
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
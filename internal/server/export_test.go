@@ -0,0 +1,43 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportServers(t *testing.T) {
+	servers := []Server{
+		{Name: "Los Santos, RP", Host: "1.2.3.4", Port: 7777, Players: 10, MaxPlayers: 50, Ping: 42 * time.Millisecond, Tags: []string{"rp", "eu"}},
+		{Name: "v6", Host: "2001:db8::1", Port: 7778},
+		{Name: "=1+1", Host: "5.6.7.8", Port: 7777, Language: "@en", Gamemode: "+rp", Tags: []string{"-x"}},
+	}
+
+	tests := []struct {
+		format      string
+		want        []string
+		description string
+	}{
+		{ExportCSV, []string{
+			"name,address,host,port,players,max_players,ping_ms,passworded,version,language,gamemode,country,tags",
+			`"Los Santos, RP",1.2.3.4:7777,1.2.3.4,7777,10,50,42,false,,,,,rp;eu`,
+			"v6,[2001:db8::1]:7778,2001:db8::1,7778,0,0,0,false,,,,,",
+		}, "CSV quotes commas and joins tags"},
+		{ExportCSV, []string{"'=1+1,5.6.7.8:7777,5.6.7.8,7777,0,0,0,false,,'@en,'+rp,,'-x"}, "CSV escapes formulas"},
+		{ExportJSON, []string{`"address": "1.2.3.4:7777"`, `"ping_ms": 42`, `"tags": [`}, "JSON records"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var b strings.Builder
+			if err := ExportServers(&b, servers, tt.format); err != nil {
+				t.Fatalf("ExportServers() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, b.String())
+				}
+			}
+		})
+	}
+}
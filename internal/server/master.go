@@ -6,12 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
-	"strings"
 	"time"
 )
 
@@ -27,6 +25,8 @@ type apiServerResponse struct {
 	Gamemode   string `json:"gm"`
 	Language   string `json:"la"`
 	Password   bool   `json:"pa"`
+	Version    string `json:"vn"`
+	OpenMP     bool   `json:"omp"`
 }
 
 // TestMasterServer tests if a master server URL is reachable and returns valid JSON
@@ -144,14 +144,19 @@ func FetchFromMaster(ctx context.Context, masterURL string) ([]Server, error) {
 		}
 		host, port := splitHostPort(s.IP)
 		servers = append(servers, Server{
-			Name:        s.Hostname,
-			Host:        host,
-			Port:        port,
-			Players:     s.Players,
-			MaxPlayers:  s.MaxPlayers,
-			Passworded:  s.Password,
-			Loading:     true,
-			LastUpdated: time.Now(),
+			Name:          s.Hostname,
+			Host:          host,
+			Port:          port,
+			Players:       s.Players,
+			MaxPlayers:    s.MaxPlayers,
+			Listed:        true,
+			MasterPlayers: s.Players,
+			Passworded:    s.Password,
+			Language:      s.Language,
+			Gamemode:      s.Gamemode,
+			Version:       ParseVersion(s.Version, s.OpenMP),
+			Loading:       true,
+			LastUpdated:   time.Now(),
 		})
 	}
 
@@ -205,19 +210,12 @@ func FetchServers(ctx context.Context, masterAddr string) ([]Server, error) {
 }
 
 func splitHostPort(value string) (string, int) {
-	const defaultPort = 7777
-	host, portStr, err := net.SplitHostPort(value)
-	if err == nil {
-		if port, perr := strconv.Atoi(portStr); perr == nil {
-			return host, port
-		}
-		return host, defaultPort
+	host, portStr, err := SplitAddress(value)
+	if err != nil {
+		return value, DefaultPort
 	}
-	if host, portStr, ok := strings.Cut(value, ":"); ok {
-		if port, perr := strconv.Atoi(portStr); perr == nil {
-			return host, port
-		}
-		return host, defaultPort
+	if port, perr := strconv.Atoi(portStr); perr == nil {
+		return host, port
 	}
-	return value, defaultPort
+	return host, DefaultPort
 }
@@ -1,27 +1,81 @@
 package server
 
 import (
-	"fmt"
 	"net"
 	"time"
 )
 
 type Server struct {
-	Name        string            `json:"name"`
-	Alias       string            `json:"alias,omitempty"`
-	Host        string            `json:"host"`
-	Port        int               `json:"port"`
-	Players     int               `json:"players"`
-	MaxPlayers  int               `json:"max_players"`
-	Ping        time.Duration     `json:"ping"`
-	Passworded  bool              `json:"passworded"`
-	LastUpdated time.Time         `json:"last_updated"`
-	Loading     bool              `json:"-"`
-	Rules       map[string]string `json:"rules,omitempty"`
+	Name  string `json:"name"`
+	Alias string `json:"alias,omitempty"`
+	Host  string `json:"host"`
+	// Tags are the labels of a favorite
+	Tags []string `json:"tags,omitempty"`
+	// IP is the address a DNS hostname resolved to when last queried
+	IP         string `json:"ip,omitempty"`
+	Port       int    `json:"port"`
+	Players    int    `json:"players"`
+	MaxPlayers int    `json:"max_players"`
+	// Listed reports whether the server came from the master list, which
+	// reported MasterPlayers players
+	Listed        bool `json:"listed,omitempty"`
+	MasterPlayers int  `json:"master_players,omitempty"`
+	// Ping is the last answered probe; 0 when every probe was lost
+	Ping       time.Duration `json:"ping"`
+	PingStats  PingStats     `json:"ping_stats,omitempty"`
+	Passworded bool          `json:"passworded"`
+	Language   string        `json:"language,omitempty"`
+	Gamemode   string        `json:"gamemode,omitempty"`
+	// Country is the ISO code from the local GeoIP database; Region is only
+	// known with a city database
+	Country     string    `json:"country,omitempty"`
+	CountryName string    `json:"country_name,omitempty"`
+	Region      string    `json:"region,omitempty"`
+	LastUpdated time.Time `json:"last_updated"`
+	Loading     bool      `json:"-"`
+	// Trust is assessed by the caller from evidence across servers, see AssessTrust
+	Trust Trust `json:"-"`
+	// Favorite marks a server in the favorites list, set by the caller
+	Favorite bool `json:"-"`
+	// Marked is set by the caller for servers in a multi-selection
+	Marked bool `json:"-"`
+	// Blocked marks a server matched by the blocklist that is shown dimmed
+	Blocked bool              `json:"-"`
+	Rules   map[string]string `json:"rules,omitempty"`
+	Version Version           `json:"version,omitempty"`
+	Status  QueryStatus       `json:"status,omitempty"`
+	// Failures counts consecutive failed queries and drives the re-query backoff
+	Failures    int       `json:"failures,omitempty"`
+	LastAttempt time.Time `json:"last_attempt,omitempty"`
+	// Timing is how long the requests of the last query took
+	Timing QueryTiming `json:"-"`
+}
+
+// QueryTiming is how long single requests of a query took, without the ping
+// probes and the open.mp probe sent alongside them
+type QueryTiming struct {
+	Info time.Duration
+	// Rules is only measured by QueryServerWithRules
+	Rules time.Duration
 }
 
 func (s Server) Addr() string {
-	return fmt.Sprintf("%s:%d", s.Host, s.Port)
+	return JoinAddr(s.Host, s.Port)
+}
+
+// DisplayName returns the alias if set, otherwise the server name
+func (s Server) DisplayName() string {
+	if s.Alias != "" {
+		return s.Alias
+	}
+	return s.Name
+}
+
+// SetRules stores the server rules and re-parses the version from them,
+// keeping the open.mp flag if it was already known
+func (s *Server) SetRules(rules map[string]string) {
+	s.Rules = rules
+	s.Version = ParseVersion(rules["version"], s.Version.OpenMP)
 }
 
 func (s Server) UDPAddr() (*net.UDPAddr, error) {
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	sampquery "github.com/Southclaws/go-samp-query"
+)
+
+const (
+	// pingSamples is how many ping probes are sent per query
+	pingSamples = 3
+	// pingProbeTimeout bounds a single probe so one lost packet doesn't use up the query
+	pingProbeTimeout = 500 * time.Millisecond
+)
+
+// PingStats summarises the ping probes of the last query
+type PingStats struct {
+	Min    time.Duration `json:"min"`
+	Median time.Duration `json:"median"`
+	Max    time.Duration `json:"max"`
+	// Jitter is the mean difference between consecutive samples
+	Jitter   time.Duration `json:"jitter"`
+	Sent     int           `json:"sent"`
+	Received int           `json:"received"`
+}
+
+// Known reports whether at least one probe was answered
+func (p PingStats) Known() bool {
+	return p.Received > 0
+}
+
+// Loss returns the percentage of probes that went unanswered
+func (p PingStats) Loss() float64 {
+	if p.Sent == 0 {
+		return 0
+	}
+	return float64(p.Sent-p.Received) * 100 / float64(p.Sent)
+}
+
+// NewPingStats computes statistics for the answered samples out of sent probes
+func NewPingStats(samples []time.Duration, sent int) PingStats {
+	stats := PingStats{Sent: sent, Received: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+
+	var jitter time.Duration
+	for i := 1; i < len(samples); i++ {
+		diff := samples[i] - samples[i-1]
+		if diff < 0 {
+			diff = -diff
+		}
+		jitter += diff
+	}
+	if len(samples) > 1 {
+		stats.Jitter = jitter / time.Duration(len(samples)-1)
+	}
+
+	sorted := make([]time.Duration, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+	if mid := len(sorted) / 2; len(sorted)%2 == 1 {
+		stats.Median = sorted[mid]
+	} else {
+		stats.Median = (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return stats
+}
+
+// measurePing sends pingSamples probes and returns the last answered sample with the statistics
+func measurePing(ctx context.Context, query *sampquery.Query) (time.Duration, PingStats) {
+	samples := make([]time.Duration, 0, pingSamples)
+	sent := 0
+	for i := 0; i < pingSamples && ctx.Err() == nil; i++ {
+		probeCtx, cancel := context.WithTimeout(ctx, pingProbeTimeout)
+		ping, err := query.GetPing(probeCtx)
+		cancel()
+		if err != nil && ctx.Err() != nil {
+			// The query ran out of time; this probe says nothing about loss
+			break
+		}
+		sent++
+		if err == nil {
+			samples = append(samples, ping)
+		}
+	}
+
+	var last time.Duration
+	if len(samples) > 0 {
+		last = samples[len(samples)-1]
+	}
+	return last, NewPingStats(samples, sent)
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewPingStats(t *testing.T) {
+	ms := func(values ...int) []time.Duration {
+		out := make([]time.Duration, len(values))
+		for i, v := range values {
+			out[i] = time.Duration(v) * time.Millisecond
+		}
+		return out
+	}
+
+	tests := []struct {
+		samples     []time.Duration
+		sent        int
+		wantMedian  time.Duration
+		wantJitter  time.Duration
+		wantLoss    float64
+		description string
+	}{
+		{ms(40, 60, 50), 3, 50 * time.Millisecond, 15 * time.Millisecond, 0, "Odd number of samples"},
+		{ms(40, 60), 3, 50 * time.Millisecond, 20 * time.Millisecond, 100.0 / 3, "Even number of samples with one lost"},
+		{ms(), 3, 0, 0, 100, "All probes lost"},
+		{ms(), 0, 0, 0, 0, "No probes sent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			stats := NewPingStats(tt.samples, tt.sent)
+			if stats.Median != tt.wantMedian {
+				t.Errorf("median = %v, want %v", stats.Median, tt.wantMedian)
+			}
+			if stats.Jitter != tt.wantJitter {
+				t.Errorf("jitter = %v, want %v", stats.Jitter, tt.wantJitter)
+			}
+			if stats.Loss() != tt.wantLoss {
+				t.Errorf("loss = %v, want %v", stats.Loss(), tt.wantLoss)
+			}
+		})
+	}
+}
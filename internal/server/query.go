@@ -2,8 +2,8 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"net"
-	"strconv"
 	"time"
 
 	sampquery "github.com/Southclaws/go-samp-query"
@@ -11,11 +11,14 @@ import (
 
 const (
 	queryTimeout = 1500 * time.Millisecond
+
+	// SA-MP servers never answer the open.mp extension, so the probe is bounded
+	minOpenMPProbe = 150 * time.Millisecond
+	maxOpenMPProbe = 500 * time.Millisecond
 )
 
 func QueryServer(ctx context.Context, host string, port int) (Server, error) {
-	addr := net.JoinHostPort(host, itoa(port))
-	query, err := sampquery.NewQuery(addr)
+	query, ip, err := newQuery(ctx, host, port)
 	if err != nil {
 		return Server{}, err
 	}
@@ -28,33 +31,38 @@ func QueryServer(ctx context.Context, host string, port int) (Server, error) {
 	ctx, cancel := context.WithDeadline(ctx, deadline)
 	defer cancel()
 
-	info, err := query.GetInfo(ctx, true)
+	start := time.Now()
+	info, err := getInfo(ctx, query)
 	if err != nil {
 		return Server{}, err
 	}
-	ping, err := query.GetPing(ctx)
-	if err != nil {
-		ping = 0
-	}
+	timing := QueryTiming{Info: time.Since(start)}
+	ping, pingStats := measurePing(ctx, query)
 
 	server := Server{
 		Name:        info.Hostname,
 		Host:        host,
 		Port:        port,
+		IP:          resolvedIP(host, ip),
 		Players:     info.Players,
 		MaxPlayers:  info.MaxPlayers,
 		Passworded:  info.Password,
+		Language:    info.Language,
+		Gamemode:    info.Gamemode,
 		Ping:        ping,
+		PingStats:   pingStats,
 		Loading:     false,
 		LastUpdated: time.Now(),
+		Status:      StatusOK,
+		LastAttempt: time.Now(),
+		Timing:      timing,
 	}
 	return server, nil
 }
 
 // QueryServerWithRules queries server info, ping, and rules in one call
 func QueryServerWithRules(ctx context.Context, host string, port int) (Server, error) {
-	addr := net.JoinHostPort(host, itoa(port))
-	query, err := sampquery.NewQuery(addr)
+	query, ip, err := newQuery(ctx, host, port)
 	if err != nil {
 		return Server{}, err
 	}
@@ -67,17 +75,18 @@ func QueryServerWithRules(ctx context.Context, host string, port int) (Server, e
 	ctx, cancel := context.WithDeadline(ctx, deadline)
 	defer cancel()
 
-	info, err := query.GetInfo(ctx, true)
+	start := time.Now()
+	info, err := getInfo(ctx, query)
 	if err != nil {
 		return Server{}, err
 	}
-	ping, err := query.GetPing(ctx)
-	if err != nil {
-		ping = 0
-	}
+	timing := QueryTiming{Info: time.Since(start)}
+	ping, pingStats := measurePing(ctx, query)
 
 	// Fetch rules
+	start = time.Now()
 	rules, err := query.GetRules(ctx)
+	timing.Rules = time.Since(start)
 	if err != nil {
 		// Continue without rules if they fail to fetch
 		rules = nil
@@ -87,20 +96,50 @@ func QueryServerWithRules(ctx context.Context, host string, port int) (Server, e
 		Name:        info.Hostname,
 		Host:        host,
 		Port:        port,
+		IP:          resolvedIP(host, ip),
 		Players:     info.Players,
 		MaxPlayers:  info.MaxPlayers,
 		Passworded:  info.Password,
+		Language:    info.Language,
+		Gamemode:    info.Gamemode,
 		Ping:        ping,
+		PingStats:   pingStats,
 		Loading:     false,
 		LastUpdated: time.Now(),
-		Rules:       rules,
+		Status:      StatusOK,
+		LastAttempt: time.Now(),
+		Timing:      timing,
 	}
+	server.Version.OpenMP = isOpenMP(ctx, query, rules, ping)
+	server.SetRules(rules)
 	return server, nil
 }
 
+// isOpenMP reports whether the server runs open.mp, using the rules when they
+// are conclusive and the open.mp query extension otherwise
+func isOpenMP(ctx context.Context, query *sampquery.Query, rules map[string]string, ping time.Duration) bool {
+	if version, ok := rules["version"]; ok && ParseVersion(version, false).Family == FamilyOpenMP {
+		return true
+	}
+	// allow_DL is only reported by open.mp servers
+	if _, ok := rules["allow_DL"]; ok {
+		return true
+	}
+
+	timeout := 2 * ping
+	if timeout < minOpenMPProbe {
+		timeout = minOpenMPProbe
+	}
+	if timeout > maxOpenMPProbe {
+		timeout = maxOpenMPProbe
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return query.GetOmpValidity(ctx)
+}
+
 func QueryServerRules(ctx context.Context, host string, port int) (map[string]string, error) {
-	addr := net.JoinHostPort(host, itoa(port))
-	query, err := sampquery.NewQuery(addr)
+	query, _, err := newQuery(ctx, host, port)
 	if err != nil {
 		return nil, err
 	}
@@ -121,8 +160,7 @@ func QueryServerRules(ctx context.Context, host string, port int) (map[string]st
 }
 
 func QueryServerPlayers(ctx context.Context, host string, port int) ([]string, error) {
-	addr := net.JoinHostPort(host, itoa(port))
-	query, err := sampquery.NewQuery(addr)
+	query, _, err := newQuery(ctx, host, port)
 	if err != nil {
 		return nil, err
 	}
@@ -143,6 +181,47 @@ func QueryServerPlayers(ctx context.Context, host string, port int) ([]string, e
 	return players, nil
 }
 
-func itoa(v int) string {
-	return strconv.Itoa(v)
+// newQuery resolves host through the DNS cache and prepares a query for it,
+// returning the IP address that is queried
+func newQuery(ctx context.Context, host string, port int) (*sampquery.Query, string, error) {
+	ip, err := ResolveHost(ctx, host)
+	if err != nil {
+		return nil, "", wrapQueryError(err)
+	}
+	// go-samp-query writes the IPv4 address into the packet header; for IPv6 the
+	// packet would go out short and the server never answers
+	if parsed := net.ParseIP(ip); parsed == nil || parsed.To4() == nil {
+		return nil, "", &QueryError{Status: StatusUnsupported, Err: fmt.Errorf("%w: %s", ErrIPv6Unsupported, ip)}
+	}
+	query, err := sampquery.NewQuery(JoinAddr(ip, port))
+	if err != nil {
+		return nil, "", wrapQueryError(err)
+	}
+	return query, ip, nil
+}
+
+// resolvedIP returns ip when host is a DNS name, so it can be shown next to it
+func resolvedIP(host, ip string) string {
+	if IsIP(host) {
+		return ""
+	}
+	return ip
+}
+
+// getInfo queries the server info, turning a panic on a truncated reply into an error
+func getInfo(ctx context.Context, query *sampquery.Query) (info sampquery.Server, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &QueryError{Status: StatusMalformed, Err: fmt.Errorf("truncated info reply: %v", r)}
+		}
+	}()
+	info, err = query.GetInfo(ctx, true)
+	if err != nil {
+		return info, wrapQueryError(err)
+	}
+	return info, nil
+}
+
+func wrapQueryError(err error) error {
+	return &QueryError{Status: classifyError(err), Err: err}
 }
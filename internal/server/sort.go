@@ -1,36 +1,233 @@
 package server
 
-import "sort"
+import (
+	"bytes"
+	"net"
+	"sort"
+	"strings"
+)
 
 type SortMode int
 
 const (
 	SortNone SortMode = iota
 	SortPing
+	SortMedianPing
 	SortPlayers
+	SortVersion
+	SortName
+	SortAddress
+	SortFill
+	SortMaxPlayers
+	SortLastUpdated
+	SortLanguage
+	SortTrust
+	SortCountry
 )
 
-func SortServers(servers []Server, mode SortMode) {
+// SortModes lists every sort mode in the order the UI cycles through them
+var SortModes = []SortMode{
+	SortNone,
+	SortPing,
+	SortMedianPing,
+	SortPlayers,
+	SortTrust,
+	SortFill,
+	SortMaxPlayers,
+	SortName,
+	SortAddress,
+	SortVersion,
+	SortLanguage,
+	SortCountry,
+	SortLastUpdated,
+}
+
+var sortModeNames = map[SortMode]string{
+	SortNone:        "none",
+	SortPing:        "ping",
+	SortMedianPing:  "median_ping",
+	SortPlayers:     "players",
+	SortVersion:     "version",
+	SortName:        "name",
+	SortAddress:     "address",
+	SortFill:        "fill",
+	SortMaxPlayers:  "max_players",
+	SortLastUpdated: "last_updated",
+	SortLanguage:    "language",
+	SortTrust:       "trust",
+	SortCountry:     "country",
+}
+
+var sortModeLabels = map[SortMode]string{
+	SortPing:        "Ping",
+	SortMedianPing:  "Median Ping",
+	SortPlayers:     "Players",
+	SortVersion:     "Version",
+	SortName:        "Name",
+	SortAddress:     "Address",
+	SortFill:        "Fill",
+	SortMaxPlayers:  "Max Players",
+	SortLastUpdated: "Last Updated",
+	SortLanguage:    "Language",
+	SortTrust:       "Trust",
+	SortCountry:     "Country",
+}
+
+// String returns the name used to persist the mode
+func (m SortMode) String() string {
+	return sortModeNames[m]
+}
+
+// Label returns the display name of the mode
+func (m SortMode) Label() string {
+	return sortModeLabels[m]
+}
+
+// ParseSortMode parses a persisted mode name, falling back to SortNone
+func ParseSortMode(name string) SortMode {
+	for mode, n := range sortModeNames {
+		if n == name {
+			return mode
+		}
+	}
+	return SortNone
+}
+
+// DefaultDescending reports the natural direction of a mode, e.g. most players first
+func (m SortMode) DefaultDescending() bool {
+	switch m {
+	case SortPlayers, SortFill, SortMaxPlayers, SortLastUpdated, SortTrust:
+		return true
+	}
+	return false
+}
+
+// SortKey is a sort mode with its direction
+type SortKey struct {
+	Mode       SortMode
+	Descending bool
+}
+
+// SortSpec orders servers by Primary, breaking ties with Secondary
+type SortSpec struct {
+	Primary   SortKey
+	Secondary SortKey
+}
+
+// SortServers sorts servers in place. Servers with unknown values for a key,
+// such as no ping yet, always sort last regardless of direction.
+func SortServers(servers []Server, spec SortSpec) {
+	if spec.Primary.Mode == SortNone && spec.Secondary.Mode == SortNone {
+		return
+	}
+	sort.SliceStable(servers, func(i, j int) bool {
+		for _, key := range []SortKey{spec.Primary, spec.Secondary} {
+			if c := compareBy(servers[i], servers[j], key); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// compareBy compares a and b by key, returning -1 when a sorts first
+func compareBy(a, b Server, key SortKey) int {
+	aKnown, bKnown := known(a, key.Mode), known(b, key.Mode)
+	switch {
+	case !aKnown && !bKnown:
+		return 0
+	case !aKnown:
+		return 1
+	case !bKnown:
+		return -1
+	}
+
+	c := compareValues(a, b, key.Mode)
+	if key.Descending {
+		return -c
+	}
+	return c
+}
+
+func known(s Server, mode SortMode) bool {
 	switch mode {
 	case SortPing:
-		sort.SliceStable(servers, func(i, j int) bool {
-			// Push servers with 0 ping to the bottom
-			if servers[i].Ping == 0 && servers[j].Ping == 0 {
-				return false
-			}
-			if servers[i].Ping == 0 {
-				return false
-			}
-			if servers[j].Ping == 0 {
-				return true
-			}
-			return servers[i].Ping < servers[j].Ping
-		})
+		return s.Ping != 0
+	case SortMedianPing:
+		return s.PingStats.Known()
+	case SortFill:
+		return s.MaxPlayers > 0
+	case SortVersion:
+		return s.Version.Known()
+	case SortLastUpdated:
+		return !s.LastUpdated.IsZero()
+	case SortLanguage:
+		return s.Language != "" && s.Language != "-"
+	case SortCountry:
+		return s.Country != ""
+	}
+	return true
+}
+
+func compareValues(a, b Server, mode SortMode) int {
+	switch mode {
+	case SortPing:
+		return compareInts(int64(a.Ping), int64(b.Ping))
+	case SortMedianPing:
+		return compareInts(int64(a.PingStats.Median), int64(b.PingStats.Median))
 	case SortPlayers:
-		sort.SliceStable(servers, func(i, j int) bool {
-			return servers[i].Players > servers[j].Players
-		})
+		// Counts that fail the fake-player heuristics don't push a server up
+		return compareInts(int64(a.TrustedPlayers()), int64(b.TrustedPlayers()))
+	case SortMaxPlayers:
+		return compareInts(int64(a.MaxPlayers), int64(b.MaxPlayers))
+	case SortFill:
+		// Compare a.Players/a.MaxPlayers with b.Players/b.MaxPlayers without floats
+		return compareInts(int64(a.Players)*int64(b.MaxPlayers), int64(b.Players)*int64(a.MaxPlayers))
+	case SortVersion:
+		// CompareVersions orders newest first, which is the natural ascending order here
+		return CompareVersions(a.Version, b.Version)
+	case SortName:
+		return strings.Compare(strings.ToLower(a.DisplayName()), strings.ToLower(b.DisplayName()))
+	case SortAddress:
+		return compareAddresses(a, b)
+	case SortLastUpdated:
+		return a.LastUpdated.Compare(b.LastUpdated)
+	case SortLanguage:
+		return strings.Compare(strings.ToLower(a.Language), strings.ToLower(b.Language))
+	case SortTrust:
+		return compareInts(int64(a.Trust.Score()), int64(b.Trust.Score()))
+	case SortCountry:
+		return strings.Compare(strings.ToLower(a.CountryName+a.Country), strings.ToLower(b.CountryName+b.Country))
+	}
+	return 0
+}
+
+// compareAddresses orders IP addresses numerically and hostnames alphabetically after them
+func compareAddresses(a, b Server) int {
+	aIP, bIP := net.ParseIP(a.Host), net.ParseIP(b.Host)
+	switch {
+	case aIP != nil && bIP != nil:
+		if c := bytes.Compare(aIP.To16(), bIP.To16()); c != 0 {
+			return c
+		}
+	case aIP != nil:
+		return -1
+	case bIP != nil:
+		return 1
 	default:
-		return
+		if c := strings.Compare(strings.ToLower(a.Host), strings.ToLower(b.Host)); c != 0 {
+			return c
+		}
+	}
+	return compareInts(int64(a.Port), int64(b.Port))
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
 	}
+	return 0
 }
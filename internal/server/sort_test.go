@@ -0,0 +1,41 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSortServers(t *testing.T) {
+	servers := func() []Server {
+		return []Server{
+			{Name: "b", Host: "10.0.0.2", Port: 7777, Players: 10, MaxPlayers: 100, Ping: 80 * time.Millisecond},
+			{Name: "a", Host: "10.0.0.1", Port: 7777, Players: 10, MaxPlayers: 20, Ping: 0},
+			{Name: "c", Host: "10.0.0.10", Port: 7777, Players: 50, MaxPlayers: 100, Ping: 20 * time.Millisecond},
+		}
+	}
+
+	tests := []struct {
+		spec        SortSpec
+		want        []string
+		description string
+	}{
+		{SortSpec{}, []string{"b", "a", "c"}, "No sort keeps the original order"},
+		{SortSpec{Primary: SortKey{Mode: SortPing}}, []string{"c", "b", "a"}, "Ping ascending with unknown ping last"},
+		{SortSpec{Primary: SortKey{Mode: SortPing, Descending: true}}, []string{"b", "c", "a"}, "Unknown ping stays last when descending"},
+		{SortSpec{Primary: SortKey{Mode: SortPlayers, Descending: true}, Secondary: SortKey{Mode: SortName}}, []string{"c", "a", "b"}, "Secondary key breaks ties"},
+		{SortSpec{Primary: SortKey{Mode: SortFill, Descending: true}}, []string{"a", "c", "b"}, "Fill ratio descending"},
+		{SortSpec{Primary: SortKey{Mode: SortAddress}}, []string{"a", "b", "c"}, "Addresses compare numerically"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			list := servers()
+			SortServers(list, tt.spec)
+			for i, name := range tt.want {
+				if list[i].Name != name {
+					t.Fatalf("position %d = %q, want %q", i, list[i].Name, name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// QueryStatus is the outcome of the last query of a server
+type QueryStatus string
+
+const (
+	// StatusPending means the server has not been queried yet
+	StatusPending   QueryStatus = ""
+	StatusOK        QueryStatus = "ok"
+	StatusTimeout   QueryStatus = "timeout"
+	StatusRefused   QueryStatus = "refused"
+	StatusDNS       QueryStatus = "dns"
+	StatusMalformed QueryStatus = "malformed"
+	// StatusUnsupported means the server cannot be queried at all, e.g. over IPv6
+	StatusUnsupported QueryStatus = "unsupported"
+)
+
+// ErrIPv6Unsupported is returned for servers only reachable over IPv6: the
+// SA-MP query packet embeds the server address as four bytes
+var ErrIPv6Unsupported = errors.New("IPv6 servers cannot be queried")
+
+const (
+	// Failing servers are re-queried after failureBackoff, doubling per failure up to maxFailureBackoff
+	failureBackoff    = 1 * time.Minute
+	maxFailureBackoff = 1 * time.Hour
+)
+
+// Label returns a short human readable form of the status
+func (s QueryStatus) Label() string {
+	switch s {
+	case StatusTimeout:
+		return "timeout"
+	case StatusRefused:
+		return "refused"
+	case StatusDNS:
+		return "DNS failure"
+	case StatusMalformed:
+		return "bad reply"
+	case StatusUnsupported:
+		return "IPv6 unsupported"
+	default:
+		return string(s)
+	}
+}
+
+// Failed reports whether the status is one of the error states
+func (s QueryStatus) Failed() bool {
+	return s != StatusPending && s != StatusOK
+}
+
+// QueryError is returned by the query functions with the classified failure
+type QueryError struct {
+	Status QueryStatus
+	Err    error
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Status.Label(), e.Err)
+}
+
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorStatus returns the query status for an error returned by a query function
+func ErrorStatus(err error) QueryStatus {
+	if err == nil {
+		return StatusOK
+	}
+	var queryErr *QueryError
+	if errors.As(err, &queryErr) {
+		return queryErr.Status
+	}
+	return classifyError(err)
+}
+
+// classifyError maps errors from the query library, which wraps them without Unwrap support
+func classifyError(err error) QueryStatus {
+	for err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) {
+			return StatusDNS
+		}
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return StatusRefused
+		}
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) || strings.Contains(err.Error(), "timed out") {
+			return StatusTimeout
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) {
+			if netErr.Timeout() {
+				return StatusTimeout
+			}
+			return StatusRefused
+		}
+		causer, ok := err.(interface{ Cause() error })
+		if !ok {
+			break
+		}
+		err = causer.Cause()
+	}
+	return StatusMalformed
+}
+
+// MarkFailed records a failed query of the server. A server that cannot be
+// queried at all is not backed off, since retrying it sends nothing.
+func (s *Server) MarkFailed(err error) {
+	s.Status = ErrorStatus(err)
+	if s.Status != StatusUnsupported {
+		s.Failures++
+	}
+	s.LastAttempt = time.Now()
+	s.Loading = false
+}
+
+// Dead reports whether the last query of the server failed
+func (s Server) Dead() bool {
+	return s.Status.Failed()
+}
+
+// Backoff returns how long to wait before querying a failing server again
+func (s Server) Backoff() time.Duration {
+	if s.Failures == 0 {
+		return 0
+	}
+	backoff := failureBackoff
+	for i := 1; i < s.Failures && backoff < maxFailureBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxFailureBackoff {
+		backoff = maxFailureBackoff
+	}
+	return backoff
+}
+
+// InBackoff reports whether a failing server should be skipped until its backoff expires
+func (s Server) InBackoff(now time.Time) bool {
+	return s.Failures > 0 && now.Sub(s.LastAttempt) < s.Backoff()
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// timeoutError is a net.Error as returned by the query library
+type timeoutError struct{ timeout bool }
+
+func (e timeoutError) Error() string   { return "i/o failure" }
+func (e timeoutError) Timeout() bool   { return e.timeout }
+func (e timeoutError) Temporary() bool { return false }
+
+// causeError wraps an error the way the query library does, without Unwrap
+type causeError struct{ cause error }
+
+func (e causeError) Error() string { return "query failed" }
+func (e causeError) Cause() error  { return e.cause }
+
+func TestErrorStatus(t *testing.T) {
+	refused := &net.OpError{Op: "read", Net: "udp", Err: os.NewSyscallError("recvfrom", syscall.ECONNREFUSED)}
+
+	tests := []struct {
+		err         error
+		want        QueryStatus
+		description string
+	}{
+		{nil, StatusOK, "No error"},
+		{&net.DNSError{Err: "no such host", Name: "play.example.com"}, StatusDNS, "DNS failure"},
+		{fmt.Errorf("resolve: %w", &net.DNSError{Err: "no such host"}), StatusDNS, "Wrapped DNS failure"},
+		{refused, StatusRefused, "Connection refused"},
+		{context.DeadlineExceeded, StatusTimeout, "Deadline exceeded"},
+		{context.Canceled, StatusTimeout, "Cancelled"},
+		{errors.New("read timed out"), StatusTimeout, "Timed out message"},
+		{timeoutError{timeout: true}, StatusTimeout, "Network timeout"},
+		{timeoutError{timeout: false}, StatusRefused, "Other network error"},
+		{causeError{cause: &net.DNSError{Err: "no such host"}}, StatusDNS, "DNS failure behind Cause"},
+		{causeError{cause: errors.New("bad packet")}, StatusMalformed, "Unknown error behind Cause"},
+		{errors.New("unexpected reply"), StatusMalformed, "Malformed reply"},
+		{&QueryError{Status: StatusRefused, Err: errors.New("x")}, StatusRefused, "Classified query error"},
+		{fmt.Errorf("query: %w", &QueryError{Status: StatusDNS, Err: errors.New("x")}), StatusDNS, "Wrapped query error"},
+		{&QueryError{Status: StatusUnsupported, Err: ErrIPv6Unsupported}, StatusUnsupported, "IPv6 server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if got := ErrorStatus(tt.err); got != tt.want {
+				t.Errorf("ErrorStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		failures    int
+		want        time.Duration
+		description string
+	}{
+		{0, 0, "Not failing"},
+		{1, time.Minute, "First failure"},
+		{2, 2 * time.Minute, "Second failure doubles"},
+		{3, 4 * time.Minute, "Third failure doubles again"},
+		{6, 32 * time.Minute, "Below the cap"},
+		{7, time.Hour, "Capped"},
+		{100, time.Hour, "Stays capped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			srv := Server{Failures: tt.failures}
+			if got := srv.Backoff(); got != tt.want {
+				t.Errorf("Backoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInBackoff(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		srv         Server
+		want        bool
+		description string
+	}{
+		{Server{Failures: 1, LastAttempt: now.Add(-30 * time.Second)}, true, "Within the backoff"},
+		{Server{Failures: 1, LastAttempt: now.Add(-2 * time.Minute)}, false, "Backoff expired"},
+		{Server{Failures: 3, LastAttempt: now.Add(-2 * time.Minute)}, true, "Longer backoff after more failures"},
+		{Server{Failures: 0, Status: StatusOK, LastAttempt: now}, false, "Reset after a successful query"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if got := tt.srv.InBackoff(now); got != tt.want {
+				t.Errorf("InBackoff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkFailed(t *testing.T) {
+	srv := Server{Status: StatusOK, Loading: true}
+	srv.MarkFailed(context.DeadlineExceeded)
+	srv.MarkFailed(&net.DNSError{Err: "no such host"})
+
+	if srv.Status != StatusDNS {
+		t.Errorf("Status = %q, want %q", srv.Status, StatusDNS)
+	}
+	if srv.Failures != 2 {
+		t.Errorf("Failures = %d, want 2", srv.Failures)
+	}
+	if srv.Loading {
+		t.Error("Loading = true, want false")
+	}
+	if !srv.Dead() || !srv.InBackoff(time.Now()) {
+		t.Error("failed server is not dead and in backoff")
+	}
+	if got := srv.Backoff(); got != 2*time.Minute {
+		t.Errorf("Backoff() = %v, want 2m", got)
+	}
+}
+
+func TestMarkFailedUnsupported(t *testing.T) {
+	srv := Server{Status: StatusOK}
+	srv.MarkFailed(&QueryError{Status: StatusUnsupported, Err: ErrIPv6Unsupported})
+	srv.MarkFailed(&QueryError{Status: StatusUnsupported, Err: ErrIPv6Unsupported})
+
+	if srv.Status != StatusUnsupported {
+		t.Errorf("Status = %q, want %q", srv.Status, StatusUnsupported)
+	}
+	if srv.Failures != 0 || srv.InBackoff(time.Now()) {
+		t.Errorf("Failures = %d, want an unsupported server never backed off", srv.Failures)
+	}
+}
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	// MaxSlots is the player limit of SA-MP and open.mp servers
+	MaxSlots = 1000
+
+	// minNameSample is the number of matching names needed before a player
+	// list pattern counts as evidence
+	minNameSample = 5
+)
+
+// TrustLevel buckets a trust score
+type TrustLevel int
+
+const (
+	TrustHigh TrustLevel = iota
+	TrustQuestionable
+	TrustSuspicious
+)
+
+// Trust is the outcome of the fake-player heuristics. The zero value is a
+// fully trusted server.
+type Trust struct {
+	Penalty int
+	Reasons []string
+}
+
+// Score returns the trust score from 0 (fake) to 100 (no red flags)
+func (t Trust) Score() int {
+	return max(0, 100-t.Penalty)
+}
+
+// Level buckets the score: 80 and up is trusted, below 50 is suspicious
+func (t Trust) Level() TrustLevel {
+	switch score := t.Score(); {
+	case score >= 80:
+		return TrustHigh
+	case score >= 50:
+		return TrustQuestionable
+	}
+	return TrustSuspicious
+}
+
+func (t *Trust) flag(penalty int, format string, args ...any) {
+	t.Penalty += penalty
+	t.Reasons = append(t.Reasons, fmt.Sprintf(format, args...))
+}
+
+// TrustedPlayers returns the player count used when sorting by players: the
+// lower of the query and master list counts, or 0 for suspicious servers
+func (s Server) TrustedPlayers() int {
+	if s.Trust.Level() == TrustSuspicious {
+		return 0
+	}
+	if s.Listed && s.MasterPlayers < s.Players {
+		return s.MasterPlayers
+	}
+	return s.Players
+}
+
+// AssessTrust runs the fake-player heuristics. players is the player list
+// from the last query and may be nil; spread comes from HostnameSpread.
+func AssessTrust(s Server, players []string, spread map[string]int) Trust {
+	var trust Trust
+
+	// Impossible counts
+	if s.MaxPlayers > 0 && s.Players > s.MaxPlayers {
+		trust.flag(60, "Reports more players than slots (%d/%d)", s.Players, s.MaxPlayers)
+	}
+	if s.MaxPlayers > MaxSlots {
+		trust.flag(40, "Claims %d slots, above the %d player limit", s.MaxPlayers, MaxSlots)
+	}
+
+	// The master list count is a snapshot, so only large differences count
+	if s.Listed {
+		diff := s.Players - s.MasterPlayers
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > max(10, s.MaxPlayers/4) {
+			trust.flag(30, "Master list reports %d players, query reports %d", s.MasterPlayers, s.Players)
+		}
+	}
+
+	// SA-MP only lists players up to 100, so a short list is only evidence below that
+	if players != nil && s.Players >= 10 && s.Players <= 100 && len(players) < s.Players/2 {
+		trust.flag(30, "Player list has %d names but %d players are reported", len(players), s.Players)
+	}
+	if prefix, count := sequentialNames(players); count > 0 {
+		trust.flag(40, "%d players named like %s1, %s2, ...", count, prefix, prefix)
+	}
+	if count := generatedNames(players); count > 0 {
+		trust.flag(30, "%d players have generated-looking names", count)
+	}
+
+	if n := spread[normalizeHostname(s.Name)]; n >= 3 {
+		trust.flag(min(40, 10+5*n), "Same name listed on %d IPs", n)
+	}
+	return trust
+}
+
+// HostnameSpread counts the distinct IPs each server name is listed on
+func HostnameSpread(servers []Server) map[string]int {
+	ips := make(map[string]map[string]bool)
+	for _, s := range servers {
+		name := normalizeHostname(s.Name)
+		if name == "" {
+			continue
+		}
+		ip := s.IP
+		if ip == "" {
+			ip = s.Host
+		}
+		if ips[name] == nil {
+			ips[name] = make(map[string]bool)
+		}
+		ips[name][ip] = true
+	}
+
+	spread := make(map[string]int, len(ips))
+	for name, set := range ips {
+		spread[name] = len(set)
+	}
+	return spread
+}
+
+func normalizeHostname(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// sequentialNames finds the most common name prefix followed by a number,
+// e.g. Player_1, Player_2, returning it when it covers enough of the list
+func sequentialNames(players []string) (string, int) {
+	counts := make(map[string]int)
+	best, bestCount := "", 0
+	for _, name := range players {
+		prefix := strings.TrimRightFunc(name, unicode.IsDigit)
+		if prefix == "" || prefix == name {
+			continue
+		}
+		key := strings.ToLower(prefix)
+		counts[key]++
+		if counts[key] > bestCount {
+			best, bestCount = prefix, counts[key]
+		}
+	}
+	if bestCount < minNameSample || bestCount*10 < len(players)*3 {
+		return "", 0
+	}
+	return best, bestCount
+}
+
+// generatedNames counts names that look random, returning 0 unless they make
+// up a large part of the list
+func generatedNames(players []string) int {
+	count := 0
+	for _, name := range players {
+		if looksGenerated(name) {
+			count++
+		}
+	}
+	if count < minNameSample || count*10 < len(players)*4 {
+		return 0
+	}
+	return count
+}
+
+// looksGenerated reports names such as "xk3j9q2z" or "Qwrtzpl": letters
+// mixed with many digits, or long runs without vowels
+func looksGenerated(name string) bool {
+	letters, digits, vowels, switches := 0, 0, 0, 0
+	prevDigit := false
+	for i, r := range name {
+		isDigit := unicode.IsDigit(r)
+		switch {
+		case isDigit:
+			digits++
+		case unicode.IsLetter(r):
+			letters++
+			if strings.ContainsRune("aeiouyAEIOUY", r) {
+				vowels++
+			}
+		}
+		if i > 0 && isDigit != prevDigit {
+			switches++
+		}
+		prevDigit = isDigit
+	}
+	if letters >= 5 && vowels == 0 {
+		return true
+	}
+	// Digits scattered through the name rather than a trailing number
+	return letters > 0 && digits >= 3 && switches >= 4
+}
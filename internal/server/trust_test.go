@@ -0,0 +1,68 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAssessTrust(t *testing.T) {
+	names := func(format string, n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = fmt.Sprintf(format, i+1)
+		}
+		return out
+	}
+	real := []string{"Carl_Johnson", "Big_Smoke", "Ryder", "Sweet", "Kendl", "Cesar_Vialpando"}
+	generated := []string{"xk3j9q2z", "p7r2m8w4", "Brtzkl", "q1w2e3r4t", "Mnbvcxz", "Carl_Johnson"}
+
+	tests := []struct {
+		srv         Server
+		players     []string
+		spread      map[string]int
+		wantLevel   TrustLevel
+		wantReasons int
+		description string
+	}{
+		{Server{Name: "Los Santos RP", Players: 6, MaxPlayers: 50, Listed: true, MasterPlayers: 8}, real, nil, TrustHigh, 0, "Honest server"},
+		{Server{Name: "Bots", Players: 60, MaxPlayers: 50}, nil, nil, TrustSuspicious, 1, "More players than slots"},
+		{Server{Name: "Bots", Players: 900, MaxPlayers: 5000}, nil, nil, TrustQuestionable, 1, "More slots than the player limit"},
+		{Server{Name: "Bots", Players: 80, MaxPlayers: 100, Listed: true, MasterPlayers: 5}, nil, nil, TrustQuestionable, 1, "Master and query counts disagree"},
+		{Server{Name: "Bots", Players: 40, MaxPlayers: 100}, names("Player_%d", 15), nil, TrustSuspicious, 2, "Short player list with sequential names"},
+		{Server{Name: "Bots", Players: 6, MaxPlayers: 100}, generated, nil, TrustQuestionable, 1, "Generated names"},
+		{Server{Name: "  Free  Money "}, nil, map[string]int{"free money": 6}, TrustQuestionable, 1, "Same hostname on many IPs"},
+		{Server{Name: "Free Money"}, nil, map[string]int{"free money": 2}, TrustHigh, 0, "Hostname on two IPs is fine"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			trust := AssessTrust(tt.srv, tt.players, tt.spread)
+			if trust.Level() != tt.wantLevel {
+				t.Errorf("level = %v (score %d), want %v", trust.Level(), trust.Score(), tt.wantLevel)
+			}
+			if len(trust.Reasons) != tt.wantReasons {
+				t.Errorf("reasons = %q, want %d", trust.Reasons, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestTrustedPlayers(t *testing.T) {
+	tests := []struct {
+		srv         Server
+		want        int
+		description string
+	}{
+		{Server{Players: 30}, 30, "Unlisted server uses the query count"},
+		{Server{Players: 30, Listed: true, MasterPlayers: 25}, 25, "Lower master count wins"},
+		{Server{Players: 30, Trust: Trust{Penalty: 60}}, 0, "Suspicious servers count as empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if got := tt.srv.TrustedPlayers(); got != tt.want {
+				t.Errorf("TrustedPlayers() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+)
+
+// VersionFamily is the client family a server is compatible with
+type VersionFamily string
+
+const (
+	FamilyUnknown VersionFamily = ""
+	Family037     VersionFamily = "0.3.7"
+	Family03DL    VersionFamily = "0.3.DL"
+	FamilyOpenMP  VersionFamily = "open.mp"
+)
+
+// Version is the parsed "version" rule of a server
+type Version struct {
+	Family VersionFamily `json:"family,omitempty"`
+	// Build is the exact release, e.g. "0.3.7-R2" or "1.2.0.2670" for open.mp
+	Build string `json:"build,omitempty"`
+	// OpenMP is set when the server runs open.mp, even if it reports a SA-MP version
+	OpenMP bool   `json:"open_mp,omitempty"`
+	Raw    string `json:"raw,omitempty"`
+}
+
+// ParseVersion parses a "version" rule. openmp is the result of the open.mp
+// query extension or master list flag, if known.
+func ParseVersion(rule string, openmp bool) Version {
+	raw := strings.TrimSpace(rule)
+	lower := strings.ToLower(raw)
+	v := Version{Raw: raw, OpenMP: openmp}
+
+	switch {
+	case strings.HasPrefix(lower, "omp "):
+		v.Family = FamilyOpenMP
+		v.Build = strings.TrimSpace(raw[len("omp "):])
+		v.OpenMP = true
+	case strings.HasPrefix(lower, "open.mp "):
+		v.Family = FamilyOpenMP
+		v.Build = strings.TrimSpace(raw[len("open.mp "):])
+		v.OpenMP = true
+	case openmp:
+		// open.mp servers may advertise a SA-MP version for legacy clients
+		v.Family = FamilyOpenMP
+		v.Build = raw
+	case strings.Contains(lower, "0.3.dl"):
+		v.Family = Family03DL
+		v.Build = raw
+	case strings.Contains(lower, "0.3.7"):
+		v.Family = Family037
+		v.Build = raw
+	}
+	return v
+}
+
+// Known reports whether the version could be parsed into a family
+func (v Version) Known() bool {
+	return v.Family != FamilyUnknown
+}
+
+// String returns the exact version for display, e.g. "0.3.7-R2" or "open.mp 1.2.0.2670"
+func (v Version) String() string {
+	if !v.Known() {
+		return v.Raw
+	}
+	if v.Build == "" {
+		return string(v.Family)
+	}
+	if v.Family == FamilyOpenMP && !strings.HasPrefix(v.Build, "0.3") {
+		return "open.mp " + v.Build
+	}
+	return v.Build
+}
+
+// familyRank orders families newest first for sorting
+func familyRank(f VersionFamily) int {
+	switch f {
+	case FamilyOpenMP:
+		return 0
+	case Family03DL:
+		return 1
+	case Family037:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// CompareVersions orders a before b by family (open.mp, 0.3.DL, 0.3.7, unknown)
+// and then by newest build. It returns -1, 0 or 1.
+func CompareVersions(a, b Version) int {
+	ra, rb := familyRank(a.Family), familyRank(b.Family)
+	if ra != rb {
+		if ra < rb {
+			return -1
+		}
+		return 1
+	}
+	return -compareBuilds(a.Build, b.Build)
+}
+
+// compareBuilds compares builds segment by segment, numerically where possible,
+// so "1.10.0" sorts after "1.9.0" and "0.3.7-R4" after "0.3.7-R2"
+func compareBuilds(a, b string) int {
+	split := func(s string) []string {
+		parts := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+			return r == '.' || r == '-' || r == ' '
+		})
+		for i, part := range parts {
+			// Release suffixes such as "R2" compare by their number
+			if rest := strings.TrimPrefix(part, "r"); rest != part {
+				if _, err := strconv.Atoi(rest); err == nil {
+					parts[i] = rest
+				}
+			}
+		}
+		return parts
+	}
+	as, bs := split(a), split(b)
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		if aErr == nil && bErr == nil {
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+			continue
+		}
+		if c := strings.Compare(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
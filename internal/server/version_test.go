@@ -0,0 +1,62 @@
+package server
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		rule        string
+		openmp      bool
+		wantFamily  VersionFamily
+		wantBuild   string
+		wantString  string
+		description string
+	}{
+		{"0.3.7-R2", false, Family037, "0.3.7-R2", "0.3.7-R2", "SA-MP 0.3.7 release"},
+		{"0.3.DL-R1", false, Family03DL, "0.3.DL-R1", "0.3.DL-R1", "SA-MP 0.3.DL release"},
+		{"omp 1.2.0.2670", false, FamilyOpenMP, "1.2.0.2670", "open.mp 1.2.0.2670", "open.mp version rule"},
+		{"0.3.7-R2", true, FamilyOpenMP, "0.3.7-R2", "0.3.7-R2", "open.mp detected by extension with legacy version"},
+		{"", true, FamilyOpenMP, "", "open.mp", "open.mp detected without version rule"},
+		{"custom", false, FamilyUnknown, "", "custom", "Unrecognised version stays unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			v := ParseVersion(tt.rule, tt.openmp)
+			if v.Family != tt.wantFamily {
+				t.Errorf("ParseVersion(%q, %v) family = %q, want %q", tt.rule, tt.openmp, v.Family, tt.wantFamily)
+			}
+			if v.Build != tt.wantBuild {
+				t.Errorf("ParseVersion(%q, %v) build = %q, want %q", tt.rule, tt.openmp, v.Build, tt.wantBuild)
+			}
+			if v.String() != tt.wantString {
+				t.Errorf("ParseVersion(%q, %v) string = %q, want %q", tt.rule, tt.openmp, v.String(), tt.wantString)
+			}
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b        string
+		want        int
+		description string
+	}{
+		{"omp 1.2.0.2670", "0.3.7-R2", -1, "open.mp before SA-MP"},
+		{"0.3.DL-R1", "0.3.7-R4", -1, "0.3.DL before 0.3.7"},
+		{"0.3.7-R4", "0.3.7-R2", -1, "Newer release first"},
+		{"omp 1.10.0.1", "omp 1.9.0.1", -1, "Numeric comparison of builds"},
+		{"0.3.7-R2", "0.3.7-R2", 0, "Equal versions"},
+		{"unknown", "0.3.7-R2", 1, "Unknown versions last"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			got := CompareVersions(ParseVersion(tt.a, false), ParseVersion(tt.b, false))
+			if got != tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package tui
 
 import (
+	"cmp"
 	"context"
 	"fmt"
-	"os"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -13,36 +15,47 @@ import (
 	"github.com/rivo/tview"
 
 	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/geoip"
 	"github.com/rsetiawan7/omp-launcher-tui/internal/launcher"
 	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
 )
 
-const (
-	SERVER_VERSION_037    = "0.3.7"
-	SERVER_VERSION_03DL   = "0.3.DL"
-	SERVER_VERSION_OPENMP = "open.mp"
-)
-
 type ViewMode int
 
 const (
 	ViewMasterList ViewMode = iota
 	ViewFavorites
+	ViewRecent
 )
 
 type App struct {
-	app                 *tview.Application
-	layout              *Layout
-	cfg                 config.Config
-	servers             []server.Server
-	filtered            []server.Server
-	favorites           []server.Server
-	filteredFavorites   []server.Server
-	passwords           map[string]string
+	app               *tview.Application
+	layout            *Layout
+	cfg               config.Config
+	servers           []server.Server
+	filtered          []server.Server
+	favorites         []server.Server
+	filteredFavorites []server.Server
+	recent            []server.Server
+	filteredRecent    []server.Server
+	lastPlayed        map[string]time.Time
+	notes             config.Notes
+	passwords         map[string]string
+	// nicknames override the configured nickname per server, e.g. from a server link
+	nicknames           map[string]string
 	searchQuery         string
-	sortMode            server.SortMode
+	sortSpec            server.SortSpec
 	viewMode            ViewMode
 	versionFilters      map[string]bool
+	countryFilters      map[string]bool
+	hideDead            bool
+	blocklist           *config.BlockMatcher
+	nameSpread          map[string]int
+	playerNames         map[string][]string
+	geo                 *geoip.Reader
+	geoPath             string
+	locations           map[string]geoip.Location
+	showBlocked         bool
 	refreshLock         sync.Mutex
 	refreshing          bool
 	busy                bool
@@ -58,12 +71,34 @@ type App struct {
 	selectedServerLock  sync.Mutex
 	cancelServerUpdate  context.CancelFunc
 	pingHistory         []int64
+	pingSent            int
+	pingReceived        int
+	queryTimings        []queryTiming
 	pingHistoryLock     sync.Mutex
+	scheduler           queryScheduler
+	keymap              keymap
+	// details is the open details screen, updated with the selected server
+	details *detailsView
+	// marked holds the addresses of the multi-selection
+	marked map[string]bool
+	// screen is the terminal, captured on every draw for the clipboard
+	screen tcell.Screen
+	// inspecting is set while the GTA installation is checked before a launch
+	inspecting bool
 }
 
 func NewApp(cfg config.Config, version string, updateChecker UpdateChecker) *App {
 	application := tview.NewApplication()
+
+	// The theme must be active before the layout is built; a broken user
+	// theme falls back to the default one
+	activeTheme, themeErr := loadTheme(cfg.Theme)
+	if themeErr == nil {
+		theme = activeTheme
+	}
+	tview.Styles = theme.styles()
 	layout := NewLayout()
+	layout.SetDetailsHidden(cfg.HideDetails)
 
 	// Load active master server from master lists
 	activeMaster, err := config.GetActiveMasterList()
@@ -76,16 +111,46 @@ func NewApp(cfg config.Config, version string, updateChecker UpdateChecker) *App
 		layout:         layout,
 		cfg:            cfg,
 		passwords:      make(map[string]string),
-		sortMode:       server.SortNone,
+		nicknames:      make(map[string]string),
+		sortSpec:       sortSpecFromConfig(cfg.Sort),
 		viewMode:       ViewMasterList,
 		versionFilters: make(map[string]bool),
+		countryFilters: make(map[string]bool),
 		version:        version,
 		updateChecker:  updateChecker,
 		lastQueryTime:  make(map[string]time.Time),
+		lastPlayed:     make(map[string]time.Time),
+		playerNames:    make(map[string][]string),
+		marked:         make(map[string]bool),
 	}
+	// The layout reflows whenever the terminal is resized
+	application.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		app.screen = screen
+		layout.Resize(screen.Size())
+		return false
+	})
 	app.setKeybindings()
+	app.reloadKeymap()
 	app.layout.SetSelectionChangedFunc(app.onServerSelected)
+	// Mouse actions are ignored while busy, like the keybindings
+	app.layout.SetHeaderClickFunc(func(modes []server.SortMode) {
+		if !app.isBusy() {
+			app.sortByColumn(modes)
+		}
+	})
+	app.layout.SetDoubleClickFunc(func(row int) {
+		if !app.isBusy() {
+			app.handleConnect()
+		}
+	})
 	app.loadFavorites()
+	app.loadRecent()
+	app.loadBlocklist()
+	app.loadNotes()
+	app.reloadGeoIP()
+	if themeErr != nil {
+		app.layout.SetStatus(fmt.Sprintf("Failed to load theme, using %s: %v", theme.Name, themeErr))
+	}
 	app.updateStatusKeys()
 
 	// Show browse-only warning if enabled
@@ -114,7 +179,8 @@ func (a *App) Run() error {
 		a.RefreshServers(false) // forceRefresh=false on startup to use cache
 	}()
 
-	return a.app.SetRoot(root, true).EnableMouse(false).Run()
+	a.applyMouse()
+	return a.app.SetRoot(root, true).Run()
 }
 
 func (a *App) RefreshServers(forceRefresh bool) {
@@ -151,16 +217,21 @@ func (a *App) RefreshServers(forceRefresh bool) {
 	// Merge with existing cached data to preserve ping and other info
 	existingServers := make(map[string]server.Server)
 	for _, srv := range a.servers {
-		key := fmt.Sprintf("%s:%d", srv.Host, srv.Port)
-		existingServers[key] = srv
+		existingServers[srv.Addr()] = srv
 	}
 
 	for i := range servers {
-		key := fmt.Sprintf("%s:%d", servers[i].Host, servers[i].Port)
-		if cached, exists := existingServers[key]; exists {
+		if cached, exists := existingServers[servers[i].Addr()]; exists {
 			// Preserve cached data
 			servers[i].Ping = cached.Ping
+			servers[i].PingStats = cached.PingStats
 			servers[i].Rules = cached.Rules
+			servers[i].Status = cached.Status
+			servers[i].Failures = cached.Failures
+			servers[i].LastAttempt = cached.LastAttempt
+			if cached.Version.Known() {
+				servers[i].Version = cached.Version
+			}
 			servers[i].LastUpdated = cached.LastUpdated
 		}
 		servers[i].Loading = true
@@ -170,77 +241,114 @@ func (a *App) RefreshServers(forceRefresh bool) {
 	a.applyFilterAndSort()
 
 	a.setBusy(false, fmt.Sprintf("Loaded %d servers", len(servers)))
-	go a.queryServers(servers, forceRefresh)
+	// Query a copy; the UI goroutine keeps updating a.servers while the round runs
+	round := make([]server.Server, len(servers))
+	copy(round, servers)
+	go a.queryServers(round, forceRefresh)
 }
 
-func (a *App) queryServers(servers []server.Server, forceRefresh bool) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// queryEntry queries a listed server and its rules, keeping what the query
+// does not return, such as the master list counts. The query also sends the
+// open.mp probe, so the version filter learns open.mp servers on refresh.
+func queryEntry(ctx context.Context, entry server.Server) (server.Server, error) {
+	res, err := server.QueryServerWithRules(ctx, entry.Host, entry.Port)
+	if err != nil {
+		return entry, err
+	}
+	entry.Name = res.Name
+	entry.Players = res.Players
+	entry.MaxPlayers = res.MaxPlayers
+	entry.Ping = res.Ping
+	entry.PingStats = res.PingStats
+	entry.Passworded = res.Passworded
+	entry.Language = res.Language
+	entry.Gamemode = res.Gamemode
+	entry.Loading = false
+	entry.LastUpdated = res.LastUpdated
+	entry.IP = res.IP
+	entry.Status = res.Status
+	entry.Failures = 0
+	entry.LastAttempt = res.LastAttempt
+
+	// The master list may already know the server runs open.mp
+	entry.Version.OpenMP = entry.Version.OpenMP || res.Version.OpenMP
+	if res.Rules != nil {
+		entry.SetRules(res.Rules)
+	}
+	return entry, nil
+}
 
-	jobs := make(chan int)
-	workers := 64
-	var wg sync.WaitGroup
+// skipQuery reports whether a query round can keep the cached data of entry.
+// Without forceRefresh data from the last 24 hours is kept. A forced refresh
+// only skips servers the interrupted round it resumes already queried, unless
+// they are dead.
+func skipQuery(entry server.Server, forceRefresh, resumed bool, now time.Time) bool {
+	if forceRefresh {
+		return resumed && !entry.Dead()
+	}
+	return !entry.LastUpdated.IsZero() && now.Sub(entry.LastUpdated) < 24*time.Hour
+}
+
+func (a *App) queryServers(servers []server.Server, forceRefresh bool) {
 	var completed int32
 	var skipped int32
+	var failed int32
 	total := len(servers)
+	progress := func() string {
+		return fmt.Sprintf("Loaded from cache: %d, Updated: %d, Failed: %d of %d servers",
+			atomic.LoadInt32(&skipped), atomic.LoadInt32(&completed), atomic.LoadInt32(&failed), total)
+	}
 
-	for i := 0; i < workers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for idx := range jobs {
-				entry := servers[idx]
-
-				// Skip querying if server was updated less than 24 hours ago (only if not forcing refresh)
-				if !forceRefresh && !entry.LastUpdated.IsZero() && time.Since(entry.LastUpdated) < 24*time.Hour {
-					entry.Loading = false
-					a.updateServer(entry)
-					current := atomic.AddInt32(&skipped, 1)
-					a.app.QueueUpdateDraw(func() {
-						a.layout.SetStatus(fmt.Sprintf("Loaded from cache: %d, Updated: %d of %d servers", current, atomic.LoadInt32(&completed), total))
-					})
-					continue
-				}
-
-				res, err := server.QueryServer(ctx, entry.Host, entry.Port)
-				if err != nil {
-					continue
-				}
-				entry.Name = res.Name
-				entry.Players = res.Players
-				entry.MaxPlayers = res.MaxPlayers
-				entry.Ping = res.Ping
-				entry.Passworded = res.Passworded
-				entry.Loading = false
-				entry.LastUpdated = res.LastUpdated
-
-				// Query server rules
-				rules, err := server.QueryServerRules(ctx, entry.Host, entry.Port)
-				if err == nil {
-					entry.Rules = rules
-				}
+	handle := func(ctx context.Context, entry server.Server, resumed bool) error {
+		if skipQuery(entry, forceRefresh, resumed, time.Now()) {
+			entry.Loading = false
+			a.updateServer(entry)
+			atomic.AddInt32(&skipped, 1)
+			a.app.QueueUpdateDraw(func() {
+				a.layout.SetStatus(progress())
+			})
+			return errNotQueried
+		}
 
-				a.updateServer(entry)
+		// Back off servers that keep failing; selecting one still queries it directly
+		if entry.InBackoff(time.Now()) {
+			entry.Loading = false
+			a.updateServer(entry)
+			return errNotQueried
+		}
 
-				// Update progress
-				current := atomic.AddInt32(&completed, 1)
-				a.app.QueueUpdateDraw(func() {
-					a.layout.SetStatus(fmt.Sprintf("Loaded from cache: %d, Updated: %d of %d servers", atomic.LoadInt32(&skipped), current, total))
-				})
+		entry, err := queryEntry(ctx, entry)
+		if err != nil {
+			// A cancelled round says nothing about the server
+			if ctx.Err() != nil {
+				return err
 			}
-		}()
+			entry.MarkFailed(err)
+			atomic.AddInt32(&failed, 1)
+			a.updateServer(entry)
+			a.app.QueueUpdateDraw(func() {
+				a.layout.SetStatus(progress())
+			})
+			return err
+		}
+		a.updateServer(entry)
+
+		// Update progress
+		atomic.AddInt32(&completed, 1)
+		a.app.QueueUpdateDraw(func() {
+			a.layout.SetStatus(progress())
+		})
+		return nil
 	}
 
-	for idx := range servers {
-		jobs <- idx
+	if !a.scheduler.Run(servers, a.uiQueryPriority(), handle) {
+		// A newer refresh replaced this round; it reports progress and saves the cache
+		return
 	}
-	close(jobs)
-	wg.Wait()
 
 	a.app.QueueUpdateDraw(func() {
-		totalSkipped := atomic.LoadInt32(&skipped)
-		totalUpdated := atomic.LoadInt32(&completed)
-		a.layout.SetStatus(fmt.Sprintf("Loaded from cache: %d, Updated: %d servers", totalSkipped, totalUpdated))
+		a.layout.SetStatus(fmt.Sprintf("Loaded from cache: %d, Updated: %d, Failed: %d servers",
+			atomic.LoadInt32(&skipped), atomic.LoadInt32(&completed), atomic.LoadInt32(&failed)))
 	})
 
 	// Save cache after all servers are updated
@@ -253,15 +361,41 @@ func (a *App) queryServers(servers []server.Server, forceRefresh bool) {
 	}()
 }
 
+// annotate fills in the fields derived locally rather than queried: the
+// location, the trust score, the favorite mark and tags, and the multi-select
+// mark. It must be called from the UI goroutine.
+func (a *App) annotate(srv server.Server) server.Server {
+	srv = a.locate(srv)
+	srv.Trust = a.assessTrust(srv)
+	srv.Favorite, srv.Tags = false, nil
+	for _, fav := range a.favorites {
+		if fav.Host == srv.Host && fav.Port == srv.Port {
+			srv.Favorite = true
+			srv.Tags = fav.Tags
+			break
+		}
+	}
+	srv.Marked = a.marked[srv.Addr()]
+	return srv
+}
+
 func (a *App) updateServer(updated server.Server) {
 	a.app.QueueUpdateDraw(func() {
 		// Update in main servers list
 		for i := range a.servers {
 			if a.servers[i].Host == updated.Host && a.servers[i].Port == updated.Port {
+				// Direct query results don't carry the master list count
+				if !updated.Listed {
+					updated.Listed = a.servers[i].Listed
+					updated.MasterPlayers = a.servers[i].MasterPlayers
+				}
 				a.servers[i] = updated
 				break
 			}
 		}
+		updated = a.annotate(updated)
+
+		a.updateRecentServer(updated)
 
 		// Only update the UI if we're in master list view
 		if a.viewMode != ViewMasterList {
@@ -271,6 +405,14 @@ func (a *App) updateServer(updated server.Server) {
 		// Update in filtered list and get the position
 		for i := range a.filtered {
 			if a.filtered[i].Host == updated.Host && a.filtered[i].Port == updated.Port {
+				// The name may have changed, so a name rule may now match
+				_, updated.Blocked = a.blockRule(updated)
+				if updated.Blocked && !a.showBlocked {
+					a.filtered = slices.Delete(a.filtered, i, i+1)
+					a.layout.UpdateTable(a.filtered)
+					a.updateTableTitle()
+					break
+				}
 				a.filtered[i] = updated
 				// Update just this row in the table
 				a.layout.UpdateTableRow(i, updated)
@@ -282,6 +424,7 @@ func (a *App) updateServer(updated server.Server) {
 
 func (a *App) updateFavoriteServer(updated server.Server) {
 	a.app.QueueUpdateDraw(func() {
+		updated = a.annotate(updated)
 		// Update in favorites list
 		for i := range a.favorites {
 			if a.favorites[i].Host == updated.Host && a.favorites[i].Port == updated.Port {
@@ -332,47 +475,67 @@ func (a *App) updateFavoriteServerInFile(srv server.Server) {
 }
 
 func (a *App) applyFilterAndSort() {
+	a.nameSpread = server.HostnameSpread(a.servers)
 	filtered := make([]server.Server, 0, len(a.servers))
 	query := strings.TrimSpace(strings.ToLower(a.searchQuery))
 	for _, srv := range a.servers {
+		srv = a.annotate(srv)
 		// Apply text search filter
-		if query != "" && !strings.Contains(strings.ToLower(srv.Name), query) && !strings.Contains(strings.ToLower(srv.Addr()), query) {
+		if query != "" && !strings.Contains(strings.ToLower(srv.Name), query) && !strings.Contains(strings.ToLower(srv.Addr()), query) && !tagMatches(srv, query) && !a.noteMatches(srv, query) {
 			continue
 		}
 		// Apply version filter
 		if !a.matchesVersionFilter(srv) {
 			continue
 		}
+		if !a.matchesCountryFilter(srv) {
+			continue
+		}
+		if a.hideDead && srv.Dead() {
+			continue
+		}
+		if _, blocked := a.blockRule(srv); blocked {
+			if !a.showBlocked {
+				continue
+			}
+			srv.Blocked = true
+		}
 		filtered = append(filtered, srv)
 	}
-	server.SortServers(filtered, a.sortMode)
+	server.SortServers(filtered, a.sortSpec)
 	a.filtered = filtered
 	a.updateTableTitle()
 	a.layout.UpdateTable(filtered)
+	a.prioritizeQueries()
 }
 
 func (a *App) updateTableTitle() {
 	var title string
-	if a.viewMode == ViewFavorites {
+	switch a.viewMode {
+	case ViewFavorites:
 		title = "★ Favorites"
-	} else {
+	case ViewRecent:
+		title = "⏱ Recently Played"
+	default:
 		title = "Servers"
 	}
 
 	// Add sort mode
-	switch a.sortMode {
-	case server.SortPing:
-		title += " [Sort: Ping ↓]"
-	case server.SortPlayers:
-		title += " [Sort: Players ↓]"
+	if label := sortLabel(a.sortSpec); label != "" {
+		title += " [Sort: " + label + "]"
+	}
+	if len(a.marked) > 0 {
+		title += fmt.Sprintf(" [Marked: %d]", len(a.marked))
 	}
 
 	a.layout.SetTableTitle(title)
+	a.layout.SetColumns(a.viewColumns(a.viewMode))
+	a.layout.SetSortIndicator(a.sortSpec)
 }
 
 func (a *App) showConfigModal() {
-	form := tview.NewForm()
-	form.SetBorder(true).SetTitle("Configuration (Ctrl+B: Browse | Ctrl+T: Test | Esc: Close)")
+	form := styleForm(tview.NewForm())
+	form.SetBorder(true).SetTitle("Configuration (Ctrl+B: Browse | Ctrl+T: Test | F1: Help | Esc: Close)")
 
 	// Get active master list name
 	masterListName, err := config.GetActiveMasterListName()
@@ -388,6 +551,8 @@ func (a *App) showConfigModal() {
 		_ = config.Save(a.cfg)
 	})
 
+	installText := tview.NewTextView().SetDynamicColors(true)
+
 	// Create GTA Path field with custom keybinding and test button
 	gtaPathLabel := "GTA SA Path"
 	form.AddInputField(gtaPathLabel, a.cfg.GTAPath, 30, nil, func(text string) {
@@ -403,21 +568,12 @@ func (a *App) showConfigModal() {
 				a.cfg.GTAPath = path
 				_ = config.Save(a.cfg)
 				gtaPathItem.SetText(path)
+				a.inspectInstallation(path, installText)
 			}, a.cfg.GTAPath)
 			return nil
 		} else if event.Key() == tcell.KeyCtrlT {
-			// Test if gta_sa.exe exists
-			testPath := gtaPathItem.GetText()
-			if testPath == "" {
-				a.layout.SetStatus("GTA SA Path is empty")
-				return nil
-			}
-			exePath := testPath + "/gta_sa.exe"
-			if _, err := os.Stat(exePath); err == nil {
-				a.layout.SetStatus("✓ gta_sa.exe found")
-			} else {
-				a.layout.SetStatus("✗ gta_sa.exe not found")
-			}
+			// Inspect gta_sa.exe, client DLLs and installed mods
+			a.inspectInstallation(gtaPathItem.GetText(), installText)
 			return nil
 		}
 		return event
@@ -473,6 +629,40 @@ func (a *App) showConfigModal() {
 		_ = config.Save(a.cfg)
 	})
 
+	// Local GeoIP database for the country column; reopened when the modal closes
+	form.AddInputField("GeoIP Database", a.cfg.GeoIPDatabase, 40, nil, func(text string) {
+		a.cfg.GeoIPDatabase = text
+		_ = config.Save(a.cfg)
+	})
+	geoIPItem := form.GetFormItemByLabel("GeoIP Database").(*tview.InputField)
+	geoIPItem.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyCtrlB {
+			a.showFileBrowser("Select GeoIP database (.mmdb)", func(path string) {
+				a.cfg.GeoIPDatabase = path
+				_ = config.Save(a.cfg)
+				geoIPItem.SetText(path)
+			}, a.cfg.GeoIPDatabase)
+			return nil
+		}
+		return event
+	})
+
+	// Default mod set, used for servers without their own
+	modSets := modSetOptions()
+	modSetIndex := 0
+	for i, name := range modSets {
+		if name == a.cfg.ModSet {
+			modSetIndex = i
+		}
+	}
+	form.AddDropDown("Default Mod Set", modSets, modSetIndex, func(option string, _ int) {
+		if option == noModSet {
+			option = ""
+		}
+		a.cfg.ModSet = option
+		_ = config.Save(a.cfg)
+	})
+
 	// Browse Only checkbox
 	form.AddCheckbox("Browse Only Mode", a.cfg.BrowseOnly, func(checked bool) {
 		a.cfg.BrowseOnly = checked
@@ -484,6 +674,46 @@ func (a *App) showConfigModal() {
 		}
 	})
 
+	// Theme, applied when the modal closes
+	themes := themeNames()
+	themeIndex := 0
+	for i, name := range themes {
+		if name == a.cfg.Theme {
+			themeIndex = i
+		}
+	}
+	form.AddDropDown("Theme", themes, themeIndex, func(option string, _ int) {
+		if option == a.cfg.Theme || (a.cfg.Theme == "" && option == ThemeDark) {
+			return
+		}
+		a.cfg.Theme = option
+		_ = config.Save(a.cfg)
+		if noColor() {
+			a.layout.SetStatus("NO_COLOR is set, keeping the monochrome theme")
+		}
+	})
+
+	// Key preset, applied when the modal closes
+	presetIndex := 0
+	for i, name := range KeyPresets {
+		if name == a.cfg.Keys.Preset {
+			presetIndex = i
+		}
+	}
+	form.AddDropDown("Key Preset", KeyPresets, presetIndex, func(option string, _ int) {
+		if option == a.cfg.Keys.Preset || (a.cfg.Keys.Preset == "" && option == PresetDefault) {
+			return
+		}
+		a.cfg.Keys.Preset = option
+		_ = config.Save(a.cfg)
+	})
+
+	form.AddCheckbox("Mouse Support", !a.cfg.DisableMouse, func(checked bool) {
+		a.cfg.DisableMouse = !checked
+		_ = config.Save(a.cfg)
+		a.applyMouse()
+	})
+
 	// Add custom input capture to form for arrow key navigation and escape
 	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() {
@@ -491,6 +721,9 @@ func (a *App) showConfigModal() {
 			a.setKeybindings()
 			a.app.SetRoot(a.layout.Root(), true)
 			a.app.SetFocus(a.layout.Table())
+			a.reloadGeoIP()
+			a.reloadTheme()
+			a.reloadKeymap()
 			return nil
 		case tcell.KeyUp:
 			// Move to previous field (Tab)
@@ -502,18 +735,35 @@ func (a *App) showConfigModal() {
 		return event
 	})
 
+	var layout *tview.Flex
+
 	// Clear global keybindings for modal
 	a.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if isHelpKey(event, true) {
+			a.showDialogHelp(helpConfig, layout, a.app.GetFocus())
+			return nil
+		}
 		if event.Key() == tcell.KeyEscape {
 			a.setKeybindings()
 			a.app.SetRoot(a.layout.Root(), true)
 			a.app.SetFocus(a.layout.Table())
+			a.reloadGeoIP()
+			a.reloadTheme()
+			a.reloadKeymap()
 			return nil
 		}
 		return event
 	})
 
-	a.app.SetRoot(form, true).SetFocus(form)
+	layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true).
+		AddItem(installText, 2, 0, false)
+
+	if a.cfg.GTAPath != "" {
+		a.inspectInstallation(a.cfg.GTAPath, installText)
+	}
+
+	a.app.SetRoot(layout, true).SetFocus(form)
 }
 
 func runtimeIndex(rt config.Runtime) int {
@@ -549,107 +799,53 @@ func (a *App) isBusy() bool {
 }
 
 func (a *App) updateStatusKeys() {
-	keys := ""
-	if a.viewMode == ViewFavorites {
-		// Favorites view
-		keys = "[::b]↑↓[::] Navigate  [::b]C[::] Config  [::b]Enter[::] Connect  [::b]/[::] Search  [::b]R[::] Refresh  [::b]S[::] Sort  [::b]F[::] Master List  [::b]A[::] Add  [::b]D[::] Remove  [::b]Q[::] Quit"
-	} else {
-		// Server table is focused (default)
-		keys = "[::b]↑↓[::] Navigate  [::b]C[::] Config  [::b]Enter[::] Connect  [::b]/[::] Search  [::b]R[::] Refresh  [::b]S[::] Sort  [::b]F[::] Favorites  [::b]A[::] Add Fav  [::b]★[::] Fav Server  [::b]M[::] Master  [::b]Q[::] Quit"
+	a.layout.SetKeysText(a.keymap.statusText(a.viewMode))
+}
+
+// reloadKeymap rebuilds the keybindings from the config, reporting any problems
+func (a *App) reloadKeymap() {
+	a.keymap = buildKeymap(defaultActions(), a.cfg.Keys.Preset, a.cfg.Keys.Bindings)
+	a.updateStatusKeys()
+	if len(a.keymap.problems) > 0 {
+		a.layout.SetStatus(fmt.Sprintf("Keybinding problems: %s (see %s Help)", strings.Join(a.keymap.problems, "; "), a.keymap.keyLabel("help")))
 	}
-	a.layout.SetKeysText(keys)
 }
 
 func (a *App) setKeybindings() {
 	a.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		key := keyName(event)
 		if a.isBusy() {
-			switch event.Key() {
-			case tcell.KeyCtrlC:
+			if act, ok := a.keymap.lookup(a.viewMode, key); (ok && act.ID == "quit") || event.Key() == tcell.KeyCtrlC {
 				a.app.Stop()
-				return nil
-			case tcell.KeyRune:
-				if strings.ToLower(string(event.Rune())) == "q" {
-					a.app.Stop()
-					return nil
-				}
 			}
 			return nil
 		}
-		switch event.Key() {
-		case tcell.KeyEnter:
-			a.handleConnect()
-			return nil
-		case tcell.KeyRune:
-			// Only apply keybindings when table is focused
-			if a.app.GetFocus() != a.layout.Table() {
-				return event
-			}
-			switch strings.ToLower(string(event.Rune())) {
-			case "q":
-				a.app.Stop()
-				return nil
-			case "c":
-				a.showConfigModal()
-				return nil
-			case "r":
-				if a.viewMode == ViewFavorites {
-					go a.refreshFavorites()
-				} else {
-					go a.RefreshServers(true) // forceRefresh=true for manual refresh
-				}
-				return nil
-			case "s":
-				a.cycleSortMode()
-				return nil
-			case "p":
-				a.promptPassword()
-				return nil
-			case "u":
-				a.checkForUpdates()
-				return nil
-			case "/":
-				a.promptSearch()
-				return nil
-			case "m":
-				if a.viewMode != ViewFavorites {
-					a.showMasterListManager()
-				}
-				return nil
-			case "f":
-				a.toggleViewMode()
-				return nil
-			case "*":
-				if a.viewMode != ViewFavorites {
-					a.toggleFavorite()
-				}
-				return nil
-			case "a":
-				a.addCustomFavorite()
-				return nil
-			case "v":
-				a.showVersionFilterDialog()
-				return nil
-			case "d":
-				if a.viewMode == ViewFavorites {
-					a.toggleFavorite() // Remove from favorites
-				}
-				return nil
-			}
-		case tcell.KeyCtrlC:
-			a.app.Stop()
+		// Only apply keybindings when table is focused
+		if a.app.GetFocus() != a.layout.Table() {
+			return event
+		}
+		if act, ok := a.keymap.lookup(a.viewMode, key); ok {
+			act.Run(a)
 			return nil
 		}
 		return event
 	})
 }
 
-func (a *App) onServerSelected(row int) {
-	var list []server.Server
-	if a.viewMode == ViewFavorites {
-		list = a.filteredFavorites
-	} else {
-		list = a.filtered
+// refreshView re-queries the servers of the current view
+func (a *App) refreshView() {
+	switch a.viewMode {
+	case ViewFavorites:
+		go a.refreshFavorites()
+	case ViewRecent:
+		a.refreshRecent()
+	default:
+		go a.RefreshServers(true) // forceRefresh=true for manual refresh
 	}
+}
+
+func (a *App) onServerSelected(row int) {
+	list := a.currentList()
 
 	if row <= 0 || row-1 >= len(list) {
 		// Cancel previous update if selection is invalid
@@ -670,6 +866,25 @@ func (a *App) onServerSelected(row int) {
 
 	srv := list[row-1]
 
+	// Moving the selection may have scrolled new rows into view
+	a.prioritizeQueries()
+
+	summary := a.noteSummary(srv)
+	if location := locationLabel(srv); location != "" {
+		summary = strings.TrimSuffix(tview.Escape(location)+" | "+summary, " | ")
+	}
+	if summary != "" {
+		a.layout.SetStatus(summary)
+	}
+
+	a.layout.SetTrust(srv.Trust)
+
+	if srv.Blocked {
+		if rule, ok := a.blockRule(srv); ok {
+			a.layout.SetStatus("Blocked by " + blockRuleLabel(rule))
+		}
+	}
+
 	// Cancel previous update goroutine if different server selected
 	a.selectedServerLock.Lock()
 	if a.currentlySelected != nil && (a.currentlySelected.Host != srv.Host || a.currentlySelected.Port != srv.Port) {
@@ -679,6 +894,9 @@ func (a *App) onServerSelected(row int) {
 		// Clear ping history for new server
 		a.pingHistoryLock.Lock()
 		a.pingHistory = []int64{}
+		a.pingSent = 0
+		a.pingReceived = 0
+		a.queryTimings = nil
 		a.pingHistoryLock.Unlock()
 	}
 	a.currentlySelected = &srv
@@ -739,16 +957,23 @@ func (a *App) queryAndUpdateServer(srv server.Server) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	res, err := server.QueryServer(ctx, srv.Host, srv.Port)
+	// The selected server also gets the open.mp extension probe
+	timing := queryTiming{At: time.Now()}
+	res, err := server.QueryServerWithRules(ctx, srv.Host, srv.Port)
 	if err != nil {
+		timing.Info = time.Since(timing.At)
+		timing.Err = err
+		a.addQueryTiming(timing)
+		srv.MarkFailed(err)
+		a.updateServer(srv)
+		a.updateFavoriteServer(srv)
 		return
 	}
-
-	// Query rules and add to server
-	rules, err := server.QueryServerRules(ctx, srv.Host, srv.Port)
-	if err == nil {
-		res.Rules = rules
-	} else {
+	// Only the info and rules requests are timed, not the probes in between
+	timing.Info = res.Timing.Info
+	timing.Rules = res.Timing.Rules
+	rules := res.Rules
+	if rules == nil {
 		res.Rules = map[string]string{}
 	}
 
@@ -763,109 +988,172 @@ func (a *App) queryAndUpdateServer(srv server.Server) {
 		go a.updateFavoriteServerInFile(res)
 	}
 
-	// Add ping to history
-	pingMs := res.Ping.Milliseconds()
+	// Add ping to history; lost probes only count towards loss
 	a.pingHistoryLock.Lock()
-	a.pingHistory = append(a.pingHistory, pingMs)
+	if res.PingStats.Known() {
+		a.pingHistory = append(a.pingHistory, res.PingStats.Median.Milliseconds())
+	}
 	// Keep last 50 pings
 	if len(a.pingHistory) > 50 {
 		a.pingHistory = a.pingHistory[1:]
 	}
+	a.pingSent += res.PingStats.Sent
+	a.pingReceived += res.PingStats.Received
 	history := make([]int64, len(a.pingHistory))
 	copy(history, a.pingHistory)
+	stats := res.PingStats
+	sent, received := a.pingSent, a.pingReceived
 	a.pingHistoryLock.Unlock()
 
 	// Update ping chart
 	a.app.QueueUpdateDraw(func() {
-		a.layout.SetPingChart(history)
+		a.layout.SetPingChart(history, stats, sent, received)
+		if a.details != nil {
+			// The listed server also carries the master list counts
+			merged, ok := a.knownServer(res.Host, res.Port)
+			if !ok {
+				merged = res
+			}
+			a.details.overview.SetText(a.overviewText(merged))
+			a.details.SetPing(history, stats, sent, received)
+		}
 	})
 
 	// Query players
+	start := time.Now()
 	players, err := server.QueryServerPlayers(ctx, srv.Host, srv.Port)
+	timing.Players = time.Since(start)
+	a.addQueryTiming(timing)
 	if err != nil || len(players) == 0 {
 		a.app.QueueUpdateDraw(func() {
 			a.layout.SetPlayers([]string{}, res.Players)
+			if a.details != nil {
+				fillPlayers(a.details.players, nil, res.Players)
+			}
+			a.showTrust(res)
 		})
 	} else {
 		a.app.QueueUpdateDraw(func() {
 			a.layout.SetPlayers(players, res.Players)
+			if a.details != nil {
+				fillPlayers(a.details.players, players, res.Players)
+			}
+			// The player list feeds the fake-player heuristics from now on
+			a.playerNames[res.Addr()] = players
+			a.showTrust(res)
 		})
 	}
 
-	// Query rules
-	rules, err = server.QueryServerRules(ctx, srv.Host, srv.Port)
-	if err != nil {
+	// The rules came with the info query
+	if rules == nil {
 		a.app.QueueUpdateDraw(func() {
 			a.layout.SetRules(map[string]string{})
+			if a.details != nil {
+				a.details.SetRules(map[string]string{})
+			}
 		})
 		return
 	}
 
 	a.app.QueueUpdateDraw(func() {
 		a.layout.SetRules(rules)
+		if a.details != nil {
+			a.details.SetRules(rules)
+		}
+	})
+}
+
+// addQueryTiming records how long the queries of the selected server took
+func (a *App) addQueryTiming(timing queryTiming) {
+	a.pingHistoryLock.Lock()
+	a.queryTimings = append(a.queryTimings, timing)
+	if len(a.queryTimings) > maxQueryTimings {
+		a.queryTimings = a.queryTimings[1:]
+	}
+	timings := append([]queryTiming(nil), a.queryTimings...)
+	a.pingHistoryLock.Unlock()
+
+	a.app.QueueUpdateDraw(func() {
+		if a.details != nil {
+			a.details.SetTiming(timings)
+		}
 	})
 }
 
 func (a *App) selectedServer() (server.Server, bool) {
 	row, _ := a.layout.Table().GetSelection()
-	var list []server.Server
-	if a.viewMode == ViewFavorites {
-		list = a.filteredFavorites
-	} else {
-		list = a.filtered
-	}
+	list := a.currentList()
 	if row <= 0 || row-1 >= len(list) {
 		return server.Server{}, false
 	}
 	return list[row-1], true
 }
 
-func (a *App) cycleSortMode() {
-	switch a.sortMode {
-	case server.SortNone:
-		a.sortMode = server.SortPing
-	case server.SortPing:
-		a.sortMode = server.SortPlayers
+// currentList returns the filtered server list shown in the current view
+func (a *App) currentList() []server.Server {
+	switch a.viewMode {
+	case ViewFavorites:
+		return a.filteredFavorites
+	case ViewRecent:
+		return a.filteredRecent
 	default:
-		a.sortMode = server.SortNone
+		return a.filtered
 	}
-	if a.viewMode == ViewFavorites {
+}
+
+// refreshCurrentView reapplies filters and sorting and redraws the table for the current view
+func (a *App) refreshCurrentView() {
+	a.layout.SetColumns(a.viewColumns(a.viewMode))
+	switch a.viewMode {
+	case ViewFavorites:
 		a.applyFavoritesFilterAndSort()
 		a.layout.UpdateTable(a.filteredFavorites)
 		a.updateTableTitle()
-	} else {
+	case ViewRecent:
+		a.applyRecentFilterAndSort()
+		a.layout.UpdateTable(a.filteredRecent)
+		a.updateTableTitle()
+	default:
 		a.applyFilterAndSort()
+		return
 	}
+	a.prioritizeQueries()
 }
 
 func (a *App) handleConnect() {
-	if a.cfg.BrowseOnly {
-		a.layout.SetStatus("⚠ Browse-only mode enabled. Cannot connect to servers.")
+	srv, ok := a.selectedServer()
+	if !ok {
 		return
 	}
+	a.connectTo(srv)
+}
 
-	srv, ok := a.selectedServer()
-	if !ok {
+// connectTo launches srv, asking for its password first when needed
+func (a *App) connectTo(srv server.Server) {
+	if a.cfg.BrowseOnly {
+		a.layout.SetStatus("⚠ Browse-only mode enabled. Cannot connect to servers.")
 		return
 	}
 	if srv.Passworded {
 		key := srv.Addr()
 		if a.passwords[key] == "" {
-			a.promptPassword()
+			a.promptPasswordFor(srv)
 			return
 		}
 	}
 	a.launchServer(srv)
 }
 
-func (a *App) launchServer(srv server.Server) {
+func (a *App) startLaunch(srv server.Server) {
 	password := a.passwords[srv.Addr()]
 	opts := launcher.LaunchOptions{
 		Host:     srv.Host,
 		Port:     srv.Port,
-		Nickname: a.cfg.Nickname,
+		Nickname: cmp.Or(a.nicknames[srv.Addr()], a.cfg.Nickname),
 		GTAPath:  a.cfg.GTAPath,
 		Password: password,
+		Name:     srv.Name,
+		ModSet:   config.ModSetFor(a.cfg, srv.Host, srv.Port),
 	}
 	a.app.Stop()
 	if err := launcher.Launch(a.cfg, opts); err != nil {
@@ -884,6 +1172,7 @@ func (a *App) loadFavorites() {
 		a.favorites[i] = server.Server{
 			Name:    fav.Name,
 			Alias:   fav.Alias,
+			Tags:    fav.Tags,
 			Host:    fav.Host,
 			Port:    fav.Port,
 			Loading: true,
@@ -896,12 +1185,13 @@ func (a *App) applyFavoritesFilterAndSort() {
 	filtered := make([]server.Server, 0, len(a.favorites))
 	query := strings.TrimSpace(strings.ToLower(a.searchQuery))
 	for _, srv := range a.favorites {
+		srv = a.annotate(srv)
 		// Apply text search filter (check name, alias, and address)
 		if query != "" {
 			nameMatch := strings.Contains(strings.ToLower(srv.Name), query)
 			aliasMatch := srv.Alias != "" && strings.Contains(strings.ToLower(srv.Alias), query)
 			addrMatch := strings.Contains(strings.ToLower(srv.Addr()), query)
-			if !nameMatch && !aliasMatch && !addrMatch {
+			if !nameMatch && !aliasMatch && !addrMatch && !tagMatches(srv, query) && !a.noteMatches(srv, query) {
 				continue
 			}
 		}
@@ -909,46 +1199,69 @@ func (a *App) applyFavoritesFilterAndSort() {
 		if !a.matchesVersionFilter(srv) {
 			continue
 		}
+		if !a.matchesCountryFilter(srv) {
+			continue
+		}
+		if a.hideDead && srv.Dead() {
+			continue
+		}
 		filtered = append(filtered, srv)
 	}
-	server.SortServers(filtered, a.sortMode)
+	server.SortServers(filtered, a.sortSpec)
 	a.filteredFavorites = filtered
 }
 
+// matchesVersionFilter reports whether the server's version family or exact
+// build is one of the selected filters
 func (a *App) matchesVersionFilter(srv server.Server) bool {
 	// If no version filters are active, show all servers
 	if len(a.versionFilters) == 0 {
 		return true
 	}
-
-	// Check if server has rules
-	if len(srv.Rules) == 0 {
+	if !srv.Version.Known() {
 		return false
 	}
+	return a.versionFilters[string(srv.Version.Family)] || a.versionFilters[srv.Version.String()]
+}
 
-	// Static allowed versions
-	allowedVersions := []string{SERVER_VERSION_037, SERVER_VERSION_03DL, SERVER_VERSION_OPENMP}
-
-	// Check version rule
-	if version, ok := srv.Rules["version"]; ok {
-		for _, allowedVer := range allowedVersions {
-			ver := allowedVer
-			if allowedVer == SERVER_VERSION_OPENMP {
-				ver = "omp"
+// collectAvailableVersions returns the version families and exact builds seen
+// across all loaded servers, families first and newest build first
+func (a *App) collectAvailableVersions() []string {
+	families := make(map[server.VersionFamily]bool)
+	builds := make(map[string]server.Version)
+	for _, list := range [][]server.Server{a.servers, a.favorites, a.recent} {
+		for _, srv := range list {
+			if !srv.Version.Known() {
+				continue
 			}
-
-			if strings.Contains(version, ver) && a.versionFilters[allowedVer] {
-				return true
+			families[srv.Version.Family] = true
+			if srv.Version.Build != "" {
+				builds[srv.Version.String()] = srv.Version
 			}
 		}
 	}
 
-	return false
-}
+	versions := make([]string, 0, len(families)+len(builds))
+	for _, family := range []server.VersionFamily{server.FamilyOpenMP, server.Family03DL, server.Family037} {
+		if families[family] {
+			versions = append(versions, string(family))
+		}
+	}
 
-func (a *App) collectAvailableVersions() []string {
-	// Return static allowed versions
-	return []string{SERVER_VERSION_037, SERVER_VERSION_03DL, SERVER_VERSION_OPENMP}
+	sorted := make([]server.Version, 0, len(builds))
+	for _, v := range builds {
+		sorted = append(sorted, v)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return server.CompareVersions(sorted[i], sorted[j]) < 0
+	})
+	for _, v := range sorted {
+		// A build equal to its family name adds nothing to the list
+		if v.String() != string(v.Family) {
+			versions = append(versions, v.String())
+		}
+	}
+	return versions
 }
 
 func (a *App) updateFilterPanel() {
@@ -970,6 +1283,23 @@ func (a *App) updateFilterPanel() {
 		filters = append(filters, fmt.Sprintf("Version: %s", strings.Join(activeVersionFilters, ", ")))
 	}
 
+	if len(a.countryFilters) > 0 {
+		countries := make([]string, 0, len(a.countryFilters))
+		for code := range a.countryFilters {
+			countries = append(countries, code)
+		}
+		sort.Strings(countries)
+		filters = append(filters, fmt.Sprintf("Country: %s", strings.Join(countries, ", ")))
+	}
+
+	if a.hideDead {
+		filters = append(filters, "Hiding unreachable")
+	}
+
+	if a.showBlocked {
+		filters = append(filters, "Showing blocked")
+	}
+
 	if len(filters) == 0 {
 		a.layout.UpdateFilterPanel("No filters active")
 	} else {
@@ -978,6 +1308,18 @@ func (a *App) updateFilterPanel() {
 	}
 }
 
+// toggleHideDead hides or shows servers whose last query failed
+func (a *App) toggleHideDead() {
+	a.hideDead = !a.hideDead
+	a.updateFilterPanel()
+	a.refreshCurrentView()
+	if a.hideDead {
+		a.layout.SetStatus("Hiding servers whose last query failed")
+	} else {
+		a.layout.SetStatus("Showing all servers")
+	}
+}
+
 func (a *App) toggleViewMode() {
 	// Check if enough time has passed since last mode switch (2 second cooldown)
 	a.modeSwitchLock.Lock()
@@ -989,27 +1331,41 @@ func (a *App) toggleViewMode() {
 	a.lastModeSwitch = time.Now()
 	a.modeSwitchLock.Unlock()
 
-	if a.viewMode == ViewMasterList {
-		a.viewMode = ViewFavorites
-		a.applyFavoritesFilterAndSort()
-		a.layout.UpdateTable(a.filteredFavorites)
-		a.updateTableTitle()
-		a.layout.SetStatus(fmt.Sprintf("Switched to Favorites view (%d servers)", len(a.filteredFavorites)))
+	if a.viewMode == ViewFavorites {
+		a.switchView(ViewMasterList)
 	} else {
-		a.viewMode = ViewMasterList
-		// Don't call applyFilterAndSort because it calls updateTableTitle
-		// We want to update the title after changing view mode
-		filtered := make([]server.Server, 0, len(a.servers))
-		query := strings.TrimSpace(strings.ToLower(a.searchQuery))
-		for _, srv := range a.servers {
-			if query == "" || strings.Contains(strings.ToLower(srv.Name), query) || strings.Contains(strings.ToLower(srv.Addr()), query) {
-				filtered = append(filtered, srv)
-			}
-		}
-		server.SortServers(filtered, a.sortMode)
-		a.filtered = filtered
-		a.layout.UpdateTable(a.filtered)
-		a.updateTableTitle()
+		a.switchView(ViewFavorites)
+	}
+}
+
+// toggleRecentView switches between the recently played view and the master list
+func (a *App) toggleRecentView() {
+	a.modeSwitchLock.Lock()
+	if time.Since(a.lastModeSwitch) < 1*time.Second {
+		a.modeSwitchLock.Unlock()
+		a.layout.SetStatus("Please wait before switching modes again")
+		return
+	}
+	a.lastModeSwitch = time.Now()
+	a.modeSwitchLock.Unlock()
+
+	if a.viewMode == ViewRecent {
+		a.switchView(ViewMasterList)
+	} else {
+		a.loadRecent()
+		a.switchView(ViewRecent)
+	}
+}
+
+func (a *App) switchView(mode ViewMode) {
+	a.viewMode = mode
+	a.refreshCurrentView()
+	switch mode {
+	case ViewFavorites:
+		a.layout.SetStatus(fmt.Sprintf("Switched to Favorites view (%d servers)", len(a.filteredFavorites)))
+	case ViewRecent:
+		a.layout.SetStatus(fmt.Sprintf("Switched to Recently Played view (%d servers)", len(a.filteredRecent)))
+	default:
 		a.layout.SetStatus(fmt.Sprintf("Switched to Master List view (%d servers)", len(a.filtered)))
 	}
 	a.updateStatusKeys()
@@ -1073,13 +1429,21 @@ func (a *App) refreshFavorites() {
 				srv := a.favorites[idx]
 				res, err := server.QueryServer(ctx, srv.Host, srv.Port)
 				if err != nil {
+					a.app.QueueUpdateDraw(func() {
+						a.favorites[idx].MarkFailed(err)
+						if a.viewMode == ViewFavorites {
+							a.applyFavoritesFilterAndSort()
+							a.layout.UpdateTable(a.filteredFavorites)
+						}
+					})
 					return
 				}
+				res.Version.OpenMP = srv.Version.OpenMP
 
 				// Query rules
 				rules, err := server.QueryServerRules(ctx, srv.Host, srv.Port)
 				if err == nil {
-					res.Rules = rules
+					res.SetRules(rules)
 				} else {
 					res.Rules = map[string]string{}
 				}
@@ -1089,10 +1453,18 @@ func (a *App) refreshFavorites() {
 					a.favorites[idx].Players = res.Players
 					a.favorites[idx].MaxPlayers = res.MaxPlayers
 					a.favorites[idx].Ping = res.Ping
+					a.favorites[idx].PingStats = res.PingStats
 					a.favorites[idx].Passworded = res.Passworded
+					a.favorites[idx].Language = res.Language
+					a.favorites[idx].Gamemode = res.Gamemode
 					a.favorites[idx].Loading = false
 					a.favorites[idx].LastUpdated = res.LastUpdated
 					a.favorites[idx].Rules = res.Rules
+					a.favorites[idx].Version = res.Version
+					a.favorites[idx].IP = res.IP
+					a.favorites[idx].Status = res.Status
+					a.favorites[idx].Failures = 0
+					a.favorites[idx].LastAttempt = res.LastAttempt
 
 					if a.viewMode == ViewFavorites {
 						a.applyFavoritesFilterAndSort()
@@ -0,0 +1,237 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+// loadBlocklist compiles the persisted blocklist used by applyFilterAndSort
+func (a *App) loadBlocklist() {
+	blocklist, err := config.LoadBlocklist()
+	if err != nil {
+		a.layout.SetStatus(fmt.Sprintf("Failed to load blocklist: %v", err))
+		return
+	}
+	a.blocklist = blocklist.Matcher()
+}
+
+// blockRule returns the rule blocking the server, if any
+func (a *App) blockRule(srv server.Server) (config.BlockRule, bool) {
+	ip := srv.IP
+	if ip == "" {
+		ip = server.CachedIP(srv.Host)
+	}
+	return a.blocklist.Match(srv.Host, srv.Port, ip, srv.Name)
+}
+
+// blockRuleLabel describes a rule for status messages, e.g. "cidr 203.0.113.0/24 (fake players)"
+func blockRuleLabel(rule config.BlockRule) string {
+	label := fmt.Sprintf("%s %s", rule.Type, rule.Value)
+	if rule.Reason != "" {
+		label += fmt.Sprintf(" (%s)", rule.Reason)
+	}
+	return label
+}
+
+// toggleShowBlocked shows blocked servers dimmed instead of dropping them
+func (a *App) toggleShowBlocked() {
+	a.showBlocked = !a.showBlocked
+	a.updateFilterPanel()
+	a.refreshCurrentView()
+	if a.showBlocked {
+		a.layout.SetStatus("Showing blocked servers dimmed")
+	} else {
+		a.layout.SetStatus("Hiding blocked servers")
+	}
+}
+
+// showBlocklistManager lists the block rules and lets the user add and remove them
+func (a *App) showBlocklistManager() {
+	blocklist, err := config.LoadBlocklist()
+	if err != nil {
+		a.layout.SetStatus(fmt.Sprintf("Failed to load blocklist: %v", err))
+		return
+	}
+
+	table := tview.NewTable().SetSelectable(true, false)
+	table.SetBorder(true).SetTitle("Blocklist (A: Add | B: Block Selected Server | D: Delete | V: Show Blocked | ?: Help | Esc: Back)")
+	styleTable(table)
+
+	updateTable := func() {
+		table.Clear()
+		headers := []string{"Type", "Value", "Reason", "Added"}
+		for i, h := range headers {
+			cell := tview.NewTableCell(fmt.Sprintf("[::b]%s", h)).
+				SetSelectable(false).
+				SetExpansion(1)
+			table.SetCell(0, i, cell)
+		}
+		table.SetFixed(1, 0)
+
+		for i, rule := range blocklist.Rules {
+			row := i + 1
+			added := "-"
+			if !rule.AddedAt.IsZero() {
+				added = rule.AddedAt.Local().Format("2006-01-02")
+			}
+			table.SetCell(row, 0, tview.NewTableCell(string(rule.Type)).SetExpansion(1))
+			table.SetCell(row, 1, tview.NewTableCell(tview.Escape(rule.Value)).SetExpansion(3))
+			table.SetCell(row, 2, tview.NewTableCell(tview.Escape(rule.Reason)).SetExpansion(2))
+			table.SetCell(row, 3, tview.NewTableCell(added).SetExpansion(1))
+		}
+
+		if len(blocklist.Rules) == 0 {
+			table.SetCell(1, 0, tview.NewTableCell("No block rules. Press 'A' to add one.").SetSelectable(false))
+		}
+	}
+
+	// applyChanges recompiles the matcher so the server list reflects the new rules
+	applyChanges := func() {
+		a.blocklist = blocklist.Matcher()
+		a.refreshCurrentView()
+		updateTable()
+	}
+
+	updateTable()
+
+	// Clear app-level keybindings while in the blocklist manager
+	a.app.SetInputCapture(nil)
+
+	var dialog *tview.Flex
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := table.GetSelection()
+		idx := row - 1
+
+		if isHelpKey(event, false) {
+			a.showDialogHelp(helpBlocklist, dialog, table)
+			return nil
+		}
+
+		switch event.Key() {
+		case tcell.KeyEscape:
+			a.setKeybindings()
+			a.app.SetRoot(a.layout.Root(), true)
+			a.app.SetFocus(a.layout.Table())
+			return nil
+
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'a', 'A':
+				a.addBlockRule(config.BlockAddress, "")
+				return nil
+
+			case 'b', 'B':
+				srv, ok := a.selectedServer()
+				if !ok {
+					a.layout.SetStatus("No server selected")
+					return nil
+				}
+				a.addBlockRule(config.BlockAddress, srv.Addr())
+				return nil
+
+			case 'd', 'D':
+				if idx >= 0 && idx < len(blocklist.Rules) {
+					removed := blocklist.Rules[idx]
+					blocklist.Rules = append(blocklist.Rules[:idx], blocklist.Rules[idx+1:]...)
+					if err := config.SaveBlocklist(blocklist); err != nil {
+						a.layout.SetStatus(fmt.Sprintf("Failed to save: %v", err))
+					}
+					applyChanges()
+					a.layout.SetStatus(fmt.Sprintf("Unblocked %s", blockRuleLabel(removed)))
+				}
+				return nil
+
+			case 'v', 'V':
+				a.toggleShowBlocked()
+				return nil
+			}
+		}
+		return event
+	})
+
+	dialog = a.withButtons(table,
+		runeButton("Add", 'a'),
+		runeButton("Block Selected", 'b'),
+		runeButton("Delete", 'd'),
+		runeButton("Show Blocked", 'v'),
+		keyButton{label: "Back", key: tcell.KeyEscape},
+	)
+	a.app.SetRoot(dialog, true).SetFocus(table)
+}
+
+// addBlockRule shows a form for a new block rule, prefilled with value
+func (a *App) addBlockRule(ruleType config.BlockRuleType, value string) {
+	form := styleForm(tview.NewForm())
+	form.SetBorder(true).SetTitle("Add Block Rule")
+
+	statusText := tview.NewTextView().SetDynamicColors(true)
+	helpText := tview.NewTextView().SetDynamicColors(true)
+
+	types := make([]string, len(config.BlockRuleTypes))
+	initial := 0
+	for i, t := range config.BlockRuleTypes {
+		types[i] = string(t)
+		if t == ruleType {
+			initial = i
+		}
+	}
+	hints := map[config.BlockRuleType]string{
+		config.BlockAddress: "One server, e.g. 203.0.113.5:7777",
+		config.BlockIP:      "Every server on an IP, e.g. 203.0.113.5",
+		config.BlockCIDR:    "Every server in an IP range, e.g. 203.0.113.0/24",
+		config.BlockName:    "Regular expression on the server name, e.g. (?i)free\\s*money",
+	}
+
+	reason := ""
+	form.AddDropDown("Type:", types, initial, func(option string, _ int) {
+		ruleType = config.BlockRuleType(option)
+		helpText.SetText(mutedTag() + tview.Escape(hints[ruleType]))
+	})
+	form.AddInputField("Value:", value, 50, nil, func(text string) {
+		value = text
+	})
+	form.AddInputField("Reason:", "", 50, nil, func(text string) {
+		reason = text
+	})
+
+	form.AddButton("Save", func() {
+		rule, err := config.NewBlockRule(ruleType, value, reason)
+		if err != nil {
+			statusText.SetText(badTag() + tview.Escape(err.Error()))
+			return
+		}
+		if err := config.AddBlockRule(rule); err != nil {
+			statusText.SetText(badTag() + fmt.Sprintf("Failed to save: %s", tview.Escape(err.Error())))
+			return
+		}
+		a.loadBlocklist()
+		a.refreshCurrentView()
+		a.showBlocklistManager()
+		a.layout.SetStatus(fmt.Sprintf("Blocked %s", blockRuleLabel(rule)))
+	})
+
+	form.AddButton("Cancel", func() {
+		a.showBlocklistManager()
+	})
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.showBlocklistManager()
+			return nil
+		}
+		return event
+	})
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true).
+		AddItem(helpText, 1, 0, false).
+		AddItem(statusText, 1, 0, false)
+
+	a.app.SetInputCapture(nil)
+	a.app.SetRoot(layout, true).SetFocus(form)
+}
@@ -0,0 +1,384 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+// markedServers returns the marked servers shown in the current view, in table order
+func (a *App) markedServers() []server.Server {
+	var marked []server.Server
+	for _, srv := range a.currentList() {
+		if a.marked[srv.Addr()] {
+			marked = append(marked, srv)
+		}
+	}
+	return marked
+}
+
+// redrawMarks updates the marked state of every row of the current view
+func (a *App) redrawMarks() {
+	list := a.currentList()
+	for i := range list {
+		list[i].Marked = a.marked[list[i].Addr()]
+		a.layout.UpdateTableRow(i, list[i])
+	}
+	a.updateTableTitle()
+}
+
+// toggleMark marks or unmarks the selected server and selects the next one
+func (a *App) toggleMark() {
+	row, _ := a.layout.Table().GetSelection()
+	list := a.currentList()
+	if row <= 0 || row-1 >= len(list) {
+		return
+	}
+	addr := list[row-1].Addr()
+	if a.marked[addr] {
+		delete(a.marked, addr)
+	} else {
+		a.marked[addr] = true
+	}
+	list[row-1].Marked = a.marked[addr]
+	a.layout.UpdateTableRow(row-1, list[row-1])
+	a.updateTableTitle()
+	a.pressKey(a.layout.Table(), tcell.KeyDown, 0)
+}
+
+// markAll marks every server shown in the current view
+func (a *App) markAll() {
+	list := a.currentList()
+	for _, srv := range list {
+		a.marked[srv.Addr()] = true
+	}
+	a.redrawMarks()
+	a.layout.SetStatus(fmt.Sprintf("Marked %d servers", len(list)))
+}
+
+// invertMarks marks the unmarked servers of the current view and unmarks the others
+func (a *App) invertMarks() {
+	for _, srv := range a.currentList() {
+		if a.marked[srv.Addr()] {
+			delete(a.marked, srv.Addr())
+		} else {
+			a.marked[srv.Addr()] = true
+		}
+	}
+	a.redrawMarks()
+	a.layout.SetStatus(fmt.Sprintf("Marked %d servers", len(a.markedServers())))
+}
+
+// clearMarks unmarks every server, including those hidden by the filters
+func (a *App) clearMarks() {
+	if len(a.marked) == 0 {
+		return
+	}
+	clear(a.marked)
+	a.redrawMarks()
+	a.layout.SetStatus("Cleared the marks")
+}
+
+// closeBulk returns from a bulk dialog to the server list
+func (a *App) closeBulk() {
+	a.setKeybindings()
+	a.app.SetRoot(a.layout.Root(), true)
+	a.app.SetFocus(a.layout.Table())
+}
+
+// showBulkMenu lists the actions on the marked servers of the current view
+func (a *App) showBulkMenu() {
+	servers := a.markedServers()
+	if len(servers) == 0 {
+		a.layout.SetStatus("No servers marked. Press Space to mark the selected server.")
+		return
+	}
+
+	list := styleList(tview.NewList()).ShowSecondaryText(false)
+	list.SetBorder(true).SetTitle(fmt.Sprintf("Bulk Actions: %d Servers (Enter: Run | ?: Help | Esc: Cancel)", len(servers)))
+	list.AddItem("Add to favorites with tags", "", 'a', func() { a.bulkAddFavorites(servers) })
+	list.AddItem("Remove from favorites", "", 'd', func() { a.bulkRemoveFavorites(servers) })
+	list.AddItem("Export to JSON or CSV", "", 'e', func() { a.bulkExport(servers) })
+	list.AddItem("Copy addresses", "", 'c', func() { a.bulkCopyAddresses(servers) })
+	list.AddItem("Block", "", 'b', func() { a.bulkBlock(servers) })
+	list.AddItem("Re-query", "", 'r', func() {
+		a.closeBulk()
+		a.bulkRequery(servers)
+	})
+	list.AddItem("Clear marks", "", 'x', func() {
+		a.closeBulk()
+		a.clearMarks()
+	})
+	a.doubleClickToSelect(list)
+
+	var dialog *tview.Flex
+	a.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case isHelpKey(event, false):
+			a.showDialogHelp(helpBulk, dialog, list)
+		case event.Key() == tcell.KeyEscape:
+			a.closeBulk()
+		default:
+			return event
+		}
+		return nil
+	})
+
+	dialog = a.withButtons(list, keyButton{label: "Cancel", key: tcell.KeyEscape})
+	a.app.SetRoot(dialog, true).SetFocus(list)
+}
+
+// promptBulkInput asks for one line of text and passes it to done on Enter;
+// Esc returns to the server list
+func (a *App) promptBulkInput(title, label, initial string, done func(text string)) {
+	input := styleInput(tview.NewInputField()).
+		SetLabel(label).
+		SetText(initial).
+		SetFieldWidth(60)
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			done(strings.TrimSpace(input.GetText()))
+		}
+	})
+
+	modal := tview.NewFlex().SetDirection(tview.FlexRow)
+	modal.AddItem(input, 3, 0, true)
+	modal.SetBorder(true).SetTitle(title + " (Enter: OK | Esc: Cancel)")
+
+	a.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.closeBulk()
+			return nil
+		}
+		return event
+	})
+	a.app.SetRoot(modal, true).SetFocus(input)
+}
+
+// tagMatches reports whether a favorite tag of srv contains the lowercase query
+func tagMatches(srv server.Server, query string) bool {
+	return slices.ContainsFunc(srv.Tags, func(tag string) bool {
+		return strings.Contains(strings.ToLower(tag), query)
+	})
+}
+
+// parseTags splits a comma-separated list of tags
+func parseTags(text string) []string {
+	var tags []string
+	for _, tag := range strings.Split(text, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func favoriteServers(servers []server.Server) []config.FavoriteServer {
+	favs := make([]config.FavoriteServer, len(servers))
+	for i, srv := range servers {
+		favs[i] = config.FavoriteServer{Name: srv.Name, Host: srv.Host, Port: srv.Port}
+	}
+	return favs
+}
+
+// bulkAddFavorites adds the servers to favorites, tagging them with the tags
+// entered by the user
+func (a *App) bulkAddFavorites(servers []server.Server) {
+	a.promptBulkInput(fmt.Sprintf("Add %d Servers to Favorites", len(servers)), "Tags (comma separated, optional): ", "", func(text string) {
+		tags := parseTags(text)
+		added, err := config.AddFavoriteServers(favoriteServers(servers), tags)
+		a.closeBulk()
+		if err != nil {
+			a.layout.SetStatus(fmt.Sprintf("Failed to add favorites: %v", err))
+			return
+		}
+		a.reloadFavorites(servers)
+		status := fmt.Sprintf("Added %d servers to favorites", added)
+		if len(tags) > 0 {
+			status += fmt.Sprintf(", tagged %d with %s", len(servers), strings.Join(tags, ", "))
+		}
+		a.layout.SetStatus(status)
+	})
+}
+
+// bulkRemoveFavorites removes the servers from favorites
+func (a *App) bulkRemoveFavorites(servers []server.Server) {
+	removed, err := config.RemoveFavoriteServers(favoriteServers(servers))
+	a.closeBulk()
+	if err != nil {
+		a.layout.SetStatus(fmt.Sprintf("Failed to remove favorites: %v", err))
+		return
+	}
+	a.reloadFavorites(nil)
+	a.layout.SetStatus(fmt.Sprintf("Removed %d servers from favorites", removed))
+}
+
+// reloadFavorites reloads the favorites file, keeping the query results of the
+// favorites and of the known servers
+func (a *App) reloadFavorites(known []server.Server) {
+	results := make(map[string]server.Server, len(a.favorites)+len(known))
+	for _, srv := range known {
+		results[srv.Addr()] = srv
+	}
+	for _, srv := range a.favorites {
+		results[srv.Addr()] = srv
+	}
+	a.loadFavorites()
+	for i, fav := range a.favorites {
+		if srv, ok := results[fav.Addr()]; ok {
+			srv.Alias, srv.Tags = fav.Alias, fav.Tags
+			a.favorites[i] = srv
+		}
+	}
+	a.refreshCurrentView()
+}
+
+// bulkExport writes the servers to a JSON or CSV file, picked by its extension
+func (a *App) bulkExport(servers []server.Server) {
+	initial := "servers.json"
+	if home, err := os.UserHomeDir(); err == nil {
+		initial = filepath.Join(home, initial)
+	}
+	a.promptBulkInput(fmt.Sprintf("Export %d Servers", len(servers)), "File (.json or .csv): ", initial, func(path string) {
+		a.closeBulk()
+		if err := exportServers(path, servers); err != nil {
+			a.layout.SetStatus(fmt.Sprintf("Export failed: %v", err))
+			return
+		}
+		a.layout.SetStatus(fmt.Sprintf("Exported %d servers to %s", len(servers), path))
+	})
+}
+
+func exportServers(path string, servers []server.Server) error {
+	format, err := server.ExportFormat(path)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := server.ExportServers(f, servers, format); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// bulkCopyAddresses copies the addresses, one per line, to the clipboard. The
+// terminal must support OSC 52 clipboard escapes.
+func (a *App) bulkCopyAddresses(servers []server.Server) {
+	a.closeBulk()
+	if a.screen == nil {
+		a.layout.SetStatus("Clipboard not available")
+		return
+	}
+	addrs := make([]string, len(servers))
+	for i, srv := range servers {
+		addrs[i] = srv.Addr()
+	}
+	a.screen.SetClipboard([]byte(strings.Join(addrs, "\n")))
+	a.layout.SetStatus(fmt.Sprintf("Copied %d addresses to the clipboard", len(addrs)))
+}
+
+// bulkBlock adds an address block rule for every server that is not blocked
+// by one yet, with the reason entered by the user
+func (a *App) bulkBlock(servers []server.Server) {
+	a.promptBulkInput(fmt.Sprintf("Block %d Servers", len(servers)), "Reason (optional): ", "", func(reason string) {
+		a.closeBulk()
+		blocklist, err := config.LoadBlocklist()
+		if err != nil {
+			a.layout.SetStatus(fmt.Sprintf("Failed to load blocklist: %v", err))
+			return
+		}
+		existing := make(map[string]bool, len(blocklist.Rules))
+		for _, rule := range blocklist.Rules {
+			if rule.Type == config.BlockAddress {
+				existing[rule.Value] = true
+			}
+		}
+		var blocked []string
+		for _, srv := range servers {
+			rule, err := config.NewBlockRule(config.BlockAddress, srv.Addr(), reason)
+			if err != nil || existing[rule.Value] {
+				continue
+			}
+			existing[rule.Value] = true
+			blocklist.Rules = append(blocklist.Rules, rule)
+			blocked = append(blocked, srv.Addr())
+		}
+		if err := config.SaveBlocklist(blocklist); err != nil {
+			a.layout.SetStatus(fmt.Sprintf("Failed to save blocklist: %v", err))
+			return
+		}
+		for _, addr := range blocked {
+			delete(a.marked, addr)
+		}
+		a.loadBlocklist()
+		a.refreshCurrentView()
+		a.layout.SetStatus(fmt.Sprintf("Blocked %d servers", len(blocked)))
+	})
+}
+
+// bulkRequery queries the servers right away as a query round of its own. It
+// does not interrupt a refresh; a refresh started later replaces it and resumes
+// after the servers it already queried.
+func (a *App) bulkRequery(servers []server.Server) {
+	if a.isBusy() {
+		a.layout.SetStatus("Loading the server list; re-query once it is done")
+		return
+	}
+	a.layout.SetStatus(fmt.Sprintf("Re-querying %d servers...", len(servers)))
+	// Favorites keep their alias and tags, which the master list does not have
+	favorites := a.viewMode == ViewFavorites
+
+	go func() {
+		var failed int32
+		handle := func(ctx context.Context, srv server.Server, resumed bool) error {
+			updated, err := queryEntry(ctx, srv)
+			if err != nil {
+				// A replaced round says nothing about the server
+				if ctx.Err() != nil {
+					return err
+				}
+				updated.MarkFailed(err)
+				atomic.AddInt32(&failed, 1)
+			}
+			if !favorites {
+				a.updateServer(updated)
+				return err
+			}
+			a.updateFavoriteServer(updated)
+			if err == nil {
+				a.updateFavoriteServerInFile(updated)
+			}
+			return err
+		}
+		started, completed := a.scheduler.RunIdle(servers, nil, handle)
+
+		a.app.QueueUpdateDraw(func() {
+			switch {
+			case !started:
+				a.layout.SetStatus("Servers are still being queried; re-query once the refresh is done")
+			case !completed:
+				a.layout.SetStatus("Re-query interrupted by a refresh")
+			default:
+				status := fmt.Sprintf("Re-queried %d servers", len(servers))
+				if n := atomic.LoadInt32(&failed); n > 0 {
+					status += fmt.Sprintf(", %d failed", n)
+				}
+				a.layout.SetStatus(status)
+			}
+		})
+	}()
+}
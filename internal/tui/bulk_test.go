@@ -0,0 +1,26 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		text        string
+		want        []string
+		description string
+	}{
+		{"", nil, "No tags"},
+		{"rp", []string{"rp"}, "One tag"},
+		{" rp , dm,,  freeroam ", []string{"rp", "dm", "freeroam"}, "Spaces and empty tags are dropped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if got := parseTags(tt.text); !slices.Equal(got, tt.want) {
+				t.Errorf("parseTags(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
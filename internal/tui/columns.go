@@ -0,0 +1,418 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+// ruleColumnPrefix marks columns that show a server rule, e.g. "rule:mapname"
+const ruleColumnPrefix = "rule:"
+
+// minColumnWidth is the narrowest fixed width; anything less means auto
+const minColumnWidth = 4
+
+// Pings up to goodPing are shown in the theme's good color, up to okPing in
+// its warning color and slower ones in its bad color
+const (
+	goodPing = 100 * time.Millisecond
+	okPing   = 250 * time.Millisecond
+)
+
+// columnDef describes how a column is titled, sized and rendered
+type columnDef struct {
+	title     string
+	expansion int
+	// sortModes are the sort keys whose indicator is shown on this column
+	sortModes []server.SortMode
+	render    func(srv server.Server) string
+}
+
+var columnDefs = map[string]columnDef{
+	"name":         {"Name", 2, []server.SortMode{server.SortName}, nameLabel},
+	"alias":        {"Alias", 1, nil, func(srv server.Server) string { return orDash(srv.Alias) }},
+	"tags":         {"Tags", 1, nil, func(srv server.Server) string { return orDash(strings.Join(srv.Tags, ", ")) }},
+	"address":      {"Host", 1, []server.SortMode{server.SortAddress}, addrLabel},
+	"ping":         {"Ping", 1, []server.SortMode{server.SortPing, server.SortMedianPing}, pingLabel},
+	"players":      {"Players", 1, []server.SortMode{server.SortPlayers, server.SortFill, server.SortMaxPlayers}, playersLabel},
+	"version":      {"Version", 1, []server.SortMode{server.SortVersion}, versionLabel},
+	"language":     {"Language", 1, []server.SortMode{server.SortLanguage}, func(srv server.Server) string { return orDash(srv.Language) }},
+	"gamemode":     {"Gamemode", 1, nil, func(srv server.Server) string { return orDash(srv.Gamemode) }},
+	"lock":         {"Lock", 0, nil, lockLabel},
+	"favorite":     {"★", 0, nil, favoriteLabel},
+	"last_updated": {"Updated", 0, []server.SortMode{server.SortLastUpdated}, updatedLabel},
+	"trust":        {"Trust", 0, []server.SortMode{server.SortTrust}, trustLabel},
+	"country":      {"Country", 0, []server.SortMode{server.SortCountry}, countryLabel},
+}
+
+// columnIDs lists the built-in columns in the order the chooser offers them
+var columnIDs = []string{
+	"name", "alias", "tags", "address", "ping", "players", "version", "language",
+	"gamemode", "lock", "favorite", "last_updated", "trust", "country",
+}
+
+// defaultColumns is used by views without a saved column layout
+var defaultColumns = []config.Column{
+	{ID: "name"}, {ID: "address"}, {ID: "ping"}, {ID: "players"},
+	{ID: "version"}, {ID: "trust"}, {ID: "country"},
+}
+
+// lookupColumn returns the definition of a built-in or rule column
+func lookupColumn(id string) (columnDef, bool) {
+	if key, ok := strings.CutPrefix(id, ruleColumnPrefix); ok {
+		if key == "" {
+			return columnDef{}, false
+		}
+		return columnDef{title: key, expansion: 1, render: func(srv server.Server) string {
+			return orDash(tview.Escape(srv.Rules[key]))
+		}}, true
+	}
+	def, ok := columnDefs[id]
+	return def, ok
+}
+
+// validColumns drops unknown and repeated columns, falling back to the defaults
+func validColumns(columns []config.Column) []config.Column {
+	valid := make([]config.Column, 0, len(columns))
+	seen := make(map[string]bool)
+	for _, col := range columns {
+		if _, ok := lookupColumn(col.ID); !ok || seen[col.ID] {
+			continue
+		}
+		seen[col.ID] = true
+		valid = append(valid, config.Column{ID: col.ID, Width: max(0, col.Width)})
+	}
+	if len(valid) == 0 {
+		return append([]config.Column(nil), defaultColumns...)
+	}
+	return valid
+}
+
+// viewColumns returns the saved columns of a view
+func (a *App) viewColumns(mode ViewMode) []config.Column {
+	switch mode {
+	case ViewFavorites:
+		return validColumns(a.cfg.Columns.Favorites)
+	case ViewRecent:
+		return validColumns(a.cfg.Columns.Recent)
+	}
+	return validColumns(a.cfg.Columns.Master)
+}
+
+// saveViewColumns persists the columns of a view; nil restores the defaults
+func (a *App) saveViewColumns(mode ViewMode, columns []config.Column) error {
+	switch mode {
+	case ViewFavorites:
+		a.cfg.Columns.Favorites = columns
+	case ViewRecent:
+		a.cfg.Columns.Recent = columns
+	default:
+		a.cfg.Columns.Master = columns
+	}
+	return config.Save(a.cfg)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// nameLabel returns the alias or server name, marking locked servers
+func nameLabel(srv server.Server) string {
+	name := srv.DisplayName()
+	if name == "" {
+		name = "(unknown)"
+	}
+	// Escaped so brackets in names, and the marker itself, aren't read as color tags
+	name = tview.Escape(name)
+	if srv.Passworded {
+		name = fmt.Sprintf("%s %s%s[-]", name, theme.tag(theme.Locked), tview.Escape("[locked]"))
+	}
+	return name
+}
+
+func pingLabel(srv server.Server) string {
+	switch {
+	case srv.Dead():
+		return statusLabel(srv)
+	case !srv.Loading:
+		return pingTag(srv) + fmt.Sprintf("%d ms", srv.Ping.Milliseconds())
+	case srv.LastUpdated.IsZero():
+		return "..."
+	}
+	return "-"
+}
+
+// pingTag colors a ping by how playable it is; blocked rows stay dimmed
+func pingTag(srv server.Server) string {
+	switch {
+	case srv.Blocked:
+		return ""
+	case srv.Ping <= goodPing:
+		return goodTag()
+	case srv.Ping <= okPing:
+		return warningTag()
+	}
+	return badTag()
+}
+
+func playersLabel(srv server.Server) string {
+	switch {
+	case srv.Dead():
+		return "-"
+	case !srv.Loading:
+		return fmt.Sprintf("%d/%d", srv.Players, srv.MaxPlayers)
+	case srv.LastUpdated.IsZero():
+		return "..."
+	}
+	return "-"
+}
+
+func lockLabel(srv server.Server) string {
+	if srv.Passworded {
+		return theme.tag(theme.Locked) + "🔒"
+	}
+	return ""
+}
+
+func favoriteLabel(srv server.Server) string {
+	if srv.Favorite {
+		return theme.tag(theme.Favorite) + "★"
+	}
+	return ""
+}
+
+// updatedLabel returns how long ago the server was last updated, e.g. "5m"
+func updatedLabel(srv server.Server) string {
+	if srv.LastUpdated.IsZero() {
+		return "-"
+	}
+	age := time.Since(srv.LastUpdated)
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(age.Hours()/24))
+}
+
+// columnItem is a row of the column chooser
+type columnItem struct {
+	column config.Column
+	shown  bool
+}
+
+// columnChoices lists the shown columns in order, then the hidden built-in
+// columns and the rules seen on loaded servers
+func (a *App) columnChoices(shown []config.Column) []columnItem {
+	items := make([]columnItem, 0, len(columnIDs)+len(shown))
+	seen := make(map[string]bool)
+	for _, col := range shown {
+		items = append(items, columnItem{column: col, shown: true})
+		seen[col.ID] = true
+	}
+	for _, id := range columnIDs {
+		if !seen[id] {
+			items = append(items, columnItem{column: config.Column{ID: id}})
+			seen[id] = true
+		}
+	}
+
+	var rules []string
+	for _, list := range [][]server.Server{a.servers, a.favorites, a.recent} {
+		for _, srv := range list {
+			for key := range srv.Rules {
+				if id := ruleColumnPrefix + key; !seen[id] {
+					seen[id] = true
+					rules = append(rules, id)
+				}
+			}
+		}
+	}
+	slices.Sort(rules)
+	for _, id := range rules {
+		items = append(items, columnItem{column: config.Column{ID: id}})
+	}
+	return items
+}
+
+// showColumnChooser picks, orders and sizes the columns of the current view
+func (a *App) showColumnChooser() {
+	mode := a.viewMode
+	items := a.columnChoices(a.viewColumns(mode))
+
+	table := tview.NewTable().SetSelectable(true, false)
+	table.SetBorder(true).SetTitle("Columns (Enter: Toggle | </>: Move | -/+: Width | R: Add Rule | D: Defaults | A: Apply | ?: Help | Esc: Cancel)")
+	styleTable(table)
+
+	updateTable := func() {
+		table.Clear()
+		for i, h := range []string{"Shown", "Column", "ID", "Width"} {
+			table.SetCell(0, i, tview.NewTableCell(fmt.Sprintf("[::b]%s", h)).SetSelectable(false).SetExpansion(1))
+		}
+		table.SetFixed(1, 0)
+		for i, item := range items {
+			def, _ := lookupColumn(item.column.ID)
+			mark, width := "[ ]", "auto"
+			if item.shown {
+				mark = "[X]"
+			}
+			if item.column.Width > 0 {
+				width = fmt.Sprintf("%d", item.column.Width)
+			}
+			table.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(mark)).SetExpansion(1))
+			table.SetCell(i+1, 1, tview.NewTableCell(tview.Escape(def.title)).SetExpansion(2))
+			table.SetCell(i+1, 2, tview.NewTableCell(tview.Escape(item.column.ID)).SetExpansion(2))
+			table.SetCell(i+1, 3, tview.NewTableCell(width).SetExpansion(1))
+		}
+	}
+
+	closeDialog := func() {
+		a.setKeybindings()
+		a.app.SetRoot(a.layout.Root(), true)
+		a.app.SetFocus(a.layout.Table())
+	}
+	save := func(columns []config.Column) {
+		if err := a.saveViewColumns(mode, columns); err != nil {
+			a.layout.SetStatus(fmt.Sprintf("Failed to save columns: %v", err))
+		}
+		a.refreshCurrentView()
+		closeDialog()
+	}
+	move := func(idx, delta int) {
+		to := idx + delta
+		if idx < 0 || idx >= len(items) || to < 0 || to >= len(items) {
+			return
+		}
+		items[idx], items[to] = items[to], items[idx]
+		updateTable()
+		table.Select(to+1, 0)
+	}
+
+	var dialog *tview.Flex
+
+	// addRule asks for a rule key and adds it as a shown column
+	addRule := func() {
+		input := styleInput(tview.NewInputField()).SetLabel("Rule key: ").SetFieldWidth(30)
+		input.SetBorder(true).SetTitle("Add Rule Column (e.g. mapname, weburl)")
+		input.SetDoneFunc(func(key tcell.Key) {
+			if name := strings.TrimSpace(input.GetText()); key == tcell.KeyEnter && name != "" {
+				id := ruleColumnPrefix + name
+				idx := slices.IndexFunc(items, func(item columnItem) bool { return item.column.ID == id })
+				if idx < 0 {
+					items = append(items, columnItem{column: config.Column{ID: id}})
+					idx = len(items) - 1
+				}
+				items[idx].shown = true
+				updateTable()
+				table.Select(idx+1, 0)
+			}
+			a.app.SetRoot(dialog, true).SetFocus(table)
+		})
+		a.app.SetRoot(input, true).SetFocus(input)
+	}
+
+	dialog = a.withButtons(table,
+		keyButton{label: "Toggle", key: tcell.KeyEnter},
+		runeButton("Move Up", '<'),
+		runeButton("Move Down", '>'),
+		runeButton("Narrower", '-'),
+		runeButton("Wider", '+'),
+		runeButton("Add Rule", 'r'),
+		runeButton("Defaults", 'd'),
+		runeButton("Apply", 'a'),
+		keyButton{label: "Cancel", key: tcell.KeyEscape},
+	)
+
+	doubleClickRows(table, func(int) {
+		a.pressKey(table, tcell.KeyEnter, 0)
+	})
+
+	updateTable()
+	table.SetSelectedFunc(func(row, _ int) {
+		if idx := row - 1; idx >= 0 && idx < len(items) {
+			items[idx].shown = !items[idx].shown
+			updateTable()
+		}
+	})
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		row, _ := table.GetSelection()
+		idx := row - 1
+
+		if isHelpKey(event, false) {
+			a.showDialogHelp(helpColumns, dialog, table)
+			return nil
+		}
+
+		switch event.Key() {
+		case tcell.KeyEscape:
+			closeDialog()
+			return nil
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case ' ':
+				if idx >= 0 && idx < len(items) {
+					items[idx].shown = !items[idx].shown
+					updateTable()
+				}
+				return nil
+			case '<':
+				move(idx, -1)
+				return nil
+			case '>':
+				move(idx, 1)
+				return nil
+			case '-', '+':
+				if idx >= 0 && idx < len(items) {
+					width := items[idx].column.Width
+					if event.Rune() == '+' {
+						width = max(width+2, minColumnWidth)
+					} else if width -= 2; width < minColumnWidth {
+						width = 0
+					}
+					items[idx].column.Width = width
+					updateTable()
+				}
+				return nil
+			case 'r', 'R':
+				addRule()
+				return nil
+			case 'd', 'D':
+				save(nil)
+				a.layout.SetStatus("Restored the default columns")
+				return nil
+			case 'a', 'A':
+				var columns []config.Column
+				for _, item := range items {
+					if item.shown {
+						columns = append(columns, item.column)
+					}
+				}
+				if len(columns) == 0 {
+					a.layout.SetStatus("Select at least one column")
+					return nil
+				}
+				save(columns)
+				a.layout.SetStatus(fmt.Sprintf("Showing %d columns", len(columns)))
+				return nil
+			}
+		}
+		return event
+	})
+
+	a.app.SetInputCapture(nil)
+	a.app.SetRoot(dialog, true).SetFocus(table)
+}
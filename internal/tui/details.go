@@ -0,0 +1,283 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+// detailTabs are the tabs of the details screen, in order
+var detailTabs = []string{"Overview", "Players", "Rules", "Ping", "Timing"}
+
+// maxQueryTimings is how many queries of the selected server the Timing tab lists
+const maxQueryTimings = 20
+
+// queryTiming is how long each query of one update of the selected server took
+type queryTiming struct {
+	At      time.Time
+	Info    time.Duration
+	Players time.Duration
+	Rules   time.Duration
+	Err     error
+}
+
+// detailsView is the full-screen view of the selected server. Like the side
+// panels it is updated by the selected server's query loop while it is open.
+type detailsView struct {
+	root        *tview.Flex
+	tabBar      *tview.TextView
+	pages       *tview.Pages
+	tab         int
+	overview    *tview.TextView
+	players     *tview.Table
+	rulesFilter *tview.InputField
+	rules       *tview.Table
+	ping        *tview.TextView
+	timing      *tview.TextView
+	ruleData    map[string]string
+}
+
+func newDetailsView(title string) *detailsView {
+	d := &detailsView{
+		pages:       tview.NewPages(),
+		overview:    tview.NewTextView().SetDynamicColors(true).SetScrollable(true),
+		players:     styleTable(tview.NewTable().SetSelectable(true, false)),
+		rulesFilter: styleInput(tview.NewInputField()).SetLabel("Search: "),
+		rules:       styleTable(tview.NewTable().SetSelectable(true, false)),
+		ping:        tview.NewTextView().SetScrollable(true),
+		timing:      tview.NewTextView().SetDynamicColors(true).SetScrollable(true),
+	}
+
+	labels := make([]string, len(detailTabs))
+	for i, name := range detailTabs {
+		labels[i] = fmt.Sprintf("%d %s", i+1, name)
+	}
+	d.tabBar = newTabBar(labels)
+
+	rulesPage := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(d.rulesFilter, 1, 0, false).
+		AddItem(d.rules, 0, 1, true)
+	d.rulesFilter.SetChangedFunc(func(text string) {
+		fillRules(d.rules, d.ruleData, text)
+	})
+
+	for i, page := range []tview.Primitive{d.overview, d.players, rulesPage, d.ping, d.timing} {
+		d.pages.AddPage(strconv.Itoa(i), page, true, i == 0)
+	}
+
+	d.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(d.tabBar, 1, 0, false).
+		AddItem(d.pages, 0, 1, true)
+	d.root.SetBorder(true).SetTitle(tview.Escape(title) + " (Tab/1-5: Switch | /: Search Rules | ?: Help | Esc: Back)")
+	return d
+}
+
+// selectTab shows tab i and returns the primitive that should get the focus
+func (d *detailsView) selectTab(i int) tview.Primitive {
+	d.tab = (i + len(detailTabs)) % len(detailTabs)
+	d.pages.SwitchToPage(strconv.Itoa(d.tab))
+	d.tabBar.Highlight(strconv.Itoa(d.tab))
+	switch d.tab {
+	case 1:
+		return d.players
+	case 2:
+		return d.rules
+	case 3:
+		return d.ping
+	case 4:
+		return d.timing
+	}
+	return d.overview
+}
+
+func (d *detailsView) SetRules(rules map[string]string) {
+	d.ruleData = rules
+	fillRules(d.rules, rules, d.rulesFilter.GetText())
+}
+
+// SetPing draws the ping chart with every sample of the history
+func (d *detailsView) SetPing(pings []int64, stats server.PingStats, sent, received int) {
+	text := pingChartText(pings, stats, sent, received, 12)
+	if len(pings) > 0 {
+		samples := make([]string, len(pings))
+		for i, ping := range pings {
+			samples[i] = strconv.FormatInt(ping, 10)
+		}
+		text += fmt.Sprintf("\n\nLatest probes: min %dms, median %dms, max %dms, %d/%d answered",
+			stats.Min.Milliseconds(), stats.Median.Milliseconds(), stats.Max.Milliseconds(), stats.Received, stats.Sent)
+		text += "\nHistory (ms, oldest first): " + strings.Join(samples, " ")
+	}
+	d.ping.SetText(text)
+}
+
+// SetTiming lists the duration of every query step, newest first
+func (d *detailsView) SetTiming(timings []queryTiming) {
+	if len(timings) == 0 {
+		d.timing.SetText("No queries yet")
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "[::b]%-10s %9s %9s %9s %9s[::-]\n", "Time", "Info", "Players", "Rules", "Total")
+	for i := len(timings) - 1; i >= 0; i-- {
+		t := timings[i]
+		if t.Err != nil {
+			fmt.Fprintf(&b, "%-10s %9s %s%s[-]\n", t.At.Local().Format("15:04:05"), durationLabel(t.Info), badTag(), tview.Escape(t.Err.Error()))
+			continue
+		}
+		fmt.Fprintf(&b, "%-10s %9s %9s %9s %9s\n", t.At.Local().Format("15:04:05"),
+			durationLabel(t.Info), durationLabel(t.Players), durationLabel(t.Rules), durationLabel(t.Info+t.Players+t.Rules))
+	}
+	d.timing.SetText(b.String())
+}
+
+func durationLabel(d time.Duration) string {
+	return d.Round(100 * time.Microsecond).String()
+}
+
+// overviewText lists every known field of srv
+func (a *App) overviewText(srv server.Server) string {
+	srv = a.annotate(srv)
+	alias, tags := "-", ""
+	for _, fav := range a.favorites {
+		if fav.Host == srv.Host && fav.Port == srv.Port {
+			if fav.Alias != "" {
+				alias = fav.Alias
+			}
+			tags = strings.Join(fav.Tags, ", ")
+		}
+	}
+	ip := srv.IP
+	if ip == "" {
+		ip = server.CachedIP(srv.Host)
+	}
+	status := "ok"
+	if srv.Status.Failed() {
+		status = statusLabel(srv) + "[-]"
+	} else if srv.LastUpdated.IsZero() {
+		status = "not queried yet"
+	}
+	fields := []struct{ name, value string }{
+		{"Name", tview.Escape(srv.Name)},
+		{"Address", tview.Escape(srv.Addr())},
+		{"Resolved IP", orDash(ip)},
+		{"Status", status},
+		{"Players", fmt.Sprintf("%d/%d", srv.Players, srv.MaxPlayers)},
+		{"Master players", orDash(masterPlayersLabel(srv))},
+		{"Ping", pingLabel(srv)},
+		{"Version", tview.Escape(versionLabel(srv))},
+		{"Language", tview.Escape(orDash(srv.Language))},
+		{"Gamemode", tview.Escape(orDash(srv.Gamemode))},
+		{"Password", yesNo(srv.Passworded)},
+		{"Country", tview.Escape(orDash(locationLabel(srv)))},
+		{"Favorite", yesNo(srv.Favorite)},
+		{"Alias", tview.Escape(alias)},
+		{"Tags", tview.Escape(orDash(tags))},
+		{"Mod set", tview.Escape(orDash(config.ModSetFor(a.cfg, srv.Host, srv.Port)))},
+		{"Trust", fmt.Sprintf("%d/100", srv.Trust.Score())},
+		{"Notes", orDash(a.noteSummary(srv))},
+		{"Last updated", updatedLabel(srv)},
+	}
+	var b strings.Builder
+	for _, f := range fields {
+		fmt.Fprintf(&b, "[::b]%-15s[::-] %s\n", f.name, f.value)
+	}
+	for _, reason := range srv.Trust.Reasons {
+		fmt.Fprintf(&b, "%-15s %s•[-] %s\n", "", warningTag(), tview.Escape(reason))
+	}
+	return b.String()
+}
+
+func masterPlayersLabel(srv server.Server) string {
+	if !srv.Listed {
+		return ""
+	}
+	return strconv.Itoa(srv.MasterPlayers)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// showDetails opens the details screen of the selected server
+func (a *App) showDetails() {
+	srv, ok := a.selectedServer()
+	if !ok {
+		return
+	}
+	d := newDetailsView(srv.DisplayName())
+	d.overview.SetText(a.overviewText(srv))
+	fillPlayers(d.players, nil, srv.Players)
+	d.SetRules(srv.Rules)
+
+	a.pingHistoryLock.Lock()
+	history := append([]int64(nil), a.pingHistory...)
+	sent, received := a.pingSent, a.pingReceived
+	timings := append([]queryTiming(nil), a.queryTimings...)
+	a.pingHistoryLock.Unlock()
+	d.SetPing(history, srv.PingStats, sent, received)
+	d.SetTiming(timings)
+	// The side panel already shows the players of the last query
+	if names := a.playerNames[srv.Addr()]; len(names) > 0 {
+		fillPlayers(d.players, names, srv.Players)
+	}
+
+	closeDetails := func() {
+		a.details = nil
+		a.setKeybindings()
+		a.app.SetRoot(a.layout.Root(), true)
+		a.app.SetFocus(a.layout.Table())
+	}
+	d.tabBar.SetHighlightedFunc(func(added, _, _ []string) {
+		if len(added) == 0 {
+			return
+		}
+		// Clicking a tab focuses the bar; hand the keyboard to the tab instead
+		if i, err := strconv.Atoi(added[0]); err == nil {
+			a.app.SetFocus(d.selectTab(i))
+		}
+	})
+	d.rulesFilter.SetDoneFunc(func(tcell.Key) {
+		a.app.SetFocus(d.rules)
+	})
+
+	a.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if a.app.GetFocus() == d.rulesFilter {
+			if isHelpKey(event, true) {
+				a.showDialogHelp(helpDetails, d.root, d.rulesFilter)
+				return nil
+			}
+			return event
+		}
+		switch {
+		case isHelpKey(event, false):
+			a.showDialogHelp(helpDetails, d.root, a.app.GetFocus())
+		case event.Key() == tcell.KeyEscape, keyName(event) == "q":
+			closeDetails()
+		case event.Key() == tcell.KeyTab, event.Key() == tcell.KeyRight:
+			a.app.SetFocus(d.selectTab(d.tab + 1))
+		case event.Key() == tcell.KeyBacktab, event.Key() == tcell.KeyLeft:
+			a.app.SetFocus(d.selectTab(d.tab - 1))
+		case event.Key() == tcell.KeyRune && event.Rune() >= '1' && int(event.Rune()-'1') < len(detailTabs):
+			a.app.SetFocus(d.selectTab(int(event.Rune() - '1')))
+		case keyName(event) == "/":
+			d.selectTab(2)
+			a.app.SetFocus(d.rulesFilter)
+		default:
+			return event
+		}
+		return nil
+	})
+
+	a.details = d
+	a.app.SetRoot(d.root, true).SetFocus(d.overview)
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/doctor"
+)
+
+func (a *App) showDiagnostics() {
+	view := tview.NewTextView().SetDynamicColors(true).SetScrollable(true)
+	view.SetBorder(true).SetTitle("Diagnostics (R: Re-run | Esc: Back)")
+
+	run := func() {
+		view.SetText(warningTag() + "Running diagnostics...")
+		cfg := a.cfg
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			checks := doctor.Run(ctx, cfg)
+			a.app.QueueUpdateDraw(func() {
+				view.SetText(formatChecks(checks))
+				view.ScrollToBeginning()
+			})
+		}()
+	}
+
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			a.setKeybindings()
+			a.app.SetRoot(a.layout.Root(), true)
+			a.app.SetFocus(a.layout.Table())
+			return nil
+		case tcell.KeyRune:
+			if event.Rune() == 'r' || event.Rune() == 'R' {
+				run()
+				return nil
+			}
+		}
+		return event
+	})
+
+	// Clear app-level keybindings while the diagnostics screen is open
+	a.app.SetInputCapture(nil)
+	a.app.SetRoot(view, true).SetFocus(view)
+	run()
+}
+
+func formatChecks(checks []doctor.Check) string {
+	var b strings.Builder
+	for _, check := range checks {
+		var label string
+		switch check.Status {
+		case doctor.StatusPass:
+			label = goodTag() + "PASS[-]"
+		case doctor.StatusWarn:
+			label = warningTag() + "WARN[-]"
+		default:
+			label = badTag() + "FAIL[-]"
+		}
+		fmt.Fprintf(&b, "%s [::b]%s[::-]: %s\n", label, check.Name, tview.Escape(check.Detail))
+		if check.Fix != "" && check.Status != doctor.StatusPass {
+			fmt.Fprintf(&b, "     → %s\n", tview.Escape(check.Fix))
+		}
+	}
+	if doctor.HasFailures(checks) {
+		b.WriteString("\n" + badTag() + "Some checks failed. Fix them before connecting to a server.[-]")
+	} else {
+		b.WriteString("\n" + goodTag() + "No blocking problems found.[-]")
+	}
+	return b.String()
+}
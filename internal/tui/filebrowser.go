@@ -15,8 +15,9 @@ func (a *App) showFileBrowser(title string, onSelect func(path string), startPat
 		startPath = os.ExpandEnv("$HOME")
 	}
 
-	list := tview.NewList().ShowSecondaryText(false)
-	list.SetBorder(true).SetTitle(title + " (Esc to cancel)")
+	list := styleList(tview.NewList()).ShowSecondaryText(false)
+	list.SetBorder(true).SetTitle(title + " (?: Help | Esc: Cancel)")
+	a.doubleClickToSelect(list)
 
 	var currentPath string
 	var loadDir func(path string) error
@@ -73,7 +74,12 @@ func (a *App) showFileBrowser(title string, onSelect func(path string), startPat
 		}
 	})
 
+	var dialog *tview.Flex
 	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if isHelpKey(event, false) {
+			a.showDialogHelp(helpFileBrowser, dialog, list)
+			return nil
+		}
 		switch event.Key() {
 		case tcell.KeyEscape:
 			a.app.SetRoot(a.layout.Root(), true)
@@ -87,5 +93,9 @@ func (a *App) showFileBrowser(title string, onSelect func(path string), startPat
 	_ = loadDir(startPath)
 
 	a.app.SetInputCapture(nil)
-	a.app.SetRoot(list, true).SetFocus(list)
+	dialog = a.withButtons(list,
+		keyButton{label: "Select", key: tcell.KeyEnter},
+		keyButton{label: "Cancel", key: tcell.KeyEscape},
+	)
+	a.app.SetRoot(dialog, true).SetFocus(list)
 }
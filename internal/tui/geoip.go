@@ -0,0 +1,190 @@
+package tui
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/geoip"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+// reloadGeoIP opens the GeoIP database from the config when its path changed.
+// Without a database the country column stays empty.
+func (a *App) reloadGeoIP() {
+	path := a.cfg.GeoIPDatabase
+	if path == a.geoPath {
+		return
+	}
+	a.geoPath = path
+	a.geo = nil
+	a.locations = make(map[string]geoip.Location)
+	if path != "" {
+		reader, err := geoip.Open(path)
+		if err != nil {
+			a.layout.SetStatus(fmt.Sprintf("Failed to open GeoIP database: %v", err))
+		} else {
+			a.geo = reader
+		}
+	}
+	a.refreshCurrentView()
+}
+
+// locate fills in the country and region of srv from the GeoIP database,
+// caching lookups by IP. It must be called from the UI goroutine.
+func (a *App) locate(srv server.Server) server.Server {
+	srv.Country, srv.CountryName, srv.Region = "", "", ""
+	if a.geo == nil {
+		return srv
+	}
+
+	ip := srv.Host
+	if !server.IsIP(ip) {
+		ip = srv.IP
+		if ip == "" {
+			ip = server.CachedIP(srv.Host)
+		}
+	}
+	if ip == "" {
+		return srv
+	}
+
+	loc, ok := a.locations[ip]
+	if !ok {
+		// A failed lookup is cached as unknown so a bad record isn't decoded again
+		loc, _ = a.geo.Locate(net.ParseIP(ip))
+		a.locations[ip] = loc
+	}
+	srv.Country, srv.CountryName, srv.Region = loc.CountryCode, loc.Country, loc.Region
+	return srv
+}
+
+// locationLabel returns "Country, Region" for the status bar, or ""
+func locationLabel(srv server.Server) string {
+	if srv.Country == "" {
+		return ""
+	}
+	label := srv.CountryName
+	if label == "" {
+		label = srv.Country
+	}
+	if srv.Region != "" {
+		label += ", " + srv.Region
+	}
+	return label
+}
+
+// matchesCountryFilter reports whether the server's country is one of the selected filters
+func (a *App) matchesCountryFilter(srv server.Server) bool {
+	if len(a.countryFilters) == 0 {
+		return true
+	}
+	return a.countryFilters[srv.Country]
+}
+
+// collectAvailableCountries returns the country codes seen across all loaded
+// servers with their names, sorted by name
+func (a *App) collectAvailableCountries() ([]string, map[string]string) {
+	names := make(map[string]string)
+	for _, list := range [][]server.Server{a.servers, a.favorites, a.recent} {
+		for _, srv := range list {
+			srv = a.locate(srv)
+			if srv.Country != "" {
+				names[srv.Country] = srv.CountryName
+			}
+		}
+	}
+
+	codes := make([]string, 0, len(names))
+	for code := range names {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return strings.ToLower(names[codes[i]]) < strings.ToLower(names[codes[j]])
+	})
+	return codes, names
+}
+
+// showCountryFilterDialog picks the countries to show
+func (a *App) showCountryFilterDialog() {
+	if a.geo == nil {
+		a.layout.SetStatus("Set a GeoIP database (.mmdb) in the config to filter by country")
+		return
+	}
+	codes, names := a.collectAvailableCountries()
+	if len(codes) == 0 {
+		a.layout.SetStatus("No server countries found in the GeoIP database")
+		return
+	}
+
+	list := styleList(tview.NewList()).ShowSecondaryText(false)
+	list.SetBorder(true).SetTitle("Country Filter (Enter: Toggle | A: Apply | C: Clear | Esc: Cancel)")
+
+	selected := make(map[string]bool)
+	for code, active := range a.countryFilters {
+		selected[code] = active
+	}
+	itemText := func(code string) string {
+		mark := "[ ]"
+		if selected[code] {
+			mark = "[X]"
+		}
+		return fmt.Sprintf("%s %s %s", tview.Escape(mark), code, names[code])
+	}
+	for i, code := range codes {
+		index, code := i, code
+		list.AddItem(itemText(code), "", 0, func() {
+			selected[code] = !selected[code]
+			list.SetItemText(index, itemText(code), "")
+		})
+	}
+
+	closeDialog := func() {
+		a.setKeybindings()
+		a.app.SetRoot(a.layout.Root(), true)
+		a.app.SetFocus(a.layout.Table())
+	}
+	apply := func() {
+		a.countryFilters = make(map[string]bool)
+		for code, active := range selected {
+			if active {
+				a.countryFilters[code] = true
+			}
+		}
+		a.updateFilterPanel()
+		a.refreshCurrentView()
+		closeDialog()
+		a.layout.SetStatus(fmt.Sprintf("Applied %d country filter(s)", len(a.countryFilters)))
+	}
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			closeDialog()
+			return nil
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'a', 'A':
+				apply()
+				return nil
+			case 'c', 'C':
+				selected = make(map[string]bool)
+				apply()
+				return nil
+			}
+		}
+		return event
+	})
+
+	a.app.SetInputCapture(nil)
+	dialog := a.withButtons(list,
+		runeButton("Apply", 'a'),
+		runeButton("Clear", 'c'),
+		keyButton{label: "Cancel", key: tcell.KeyEscape},
+	)
+	a.app.SetRoot(dialog, true).SetFocus(list)
+}
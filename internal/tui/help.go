@@ -0,0 +1,224 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// helpEntry is a fixed key of a dialog; dialog keys are not part of the keymap
+type helpEntry struct {
+	keys        string
+	description string
+}
+
+// Dialogs with their own help
+const (
+	helpMasterLists = "Master List Manager"
+	helpConfig      = "Configuration"
+	helpSearch      = "Search"
+	helpFileBrowser = "File Browser"
+	helpBlocklist   = "Blocklist"
+	helpColumns     = "Columns"
+	helpPalette     = "Command Palette"
+	helpDetails     = "Server Details"
+	helpBulk        = "Bulk Actions"
+)
+
+// dialogHelp lists the keys of every dialog with help
+var dialogHelp = map[string][]helpEntry{
+	helpMasterLists: {
+		{"↑/↓", "Move between master lists"},
+		{"Enter", "Edit the selected list"},
+		{"A", "Add a master list"},
+		{"D", "Delete the selected list"},
+		{"S", "Make the selected list active"},
+		{"Double-click", "Edit a list"},
+		{"?", "Show this help"},
+		{"Esc", "Back to the server list"},
+	},
+	helpConfig: {
+		{"↑/↓, Tab", "Move between fields"},
+		{"Enter", "Open a dropdown or toggle a checkbox"},
+		{"Ctrl+B", "Browse for a path (path fields)"},
+		{"Ctrl+T", "Inspect the GTA installation (GTA SA Path)"},
+		{"F1", "Show this help"},
+		{"Esc", "Close and apply theme, keys and GeoIP changes"},
+	},
+	helpSearch: {
+		{"Text", "Match server names, addresses, notes and favorite tags"},
+		{"Enter", "Apply the search; an empty search shows every server"},
+		{"F1", "Show this help"},
+		{"Esc", "Cancel and keep the previous search"},
+	},
+	helpFileBrowser: {
+		{"↑/↓", "Move between directories"},
+		{"Enter", "Select the directory, or go up on .."},
+		{"Double-click", "Select a directory"},
+		{"?, F1", "Show this help"},
+		{"Esc", "Cancel"},
+	},
+	helpPalette: {
+		{"Text", "Find a command; letters may be skipped, e.g. chup for check_updates"},
+		{"Space", "Arguments follow the command, e.g. sort players desc"},
+		{"↑/↓", "Move between matching commands"},
+		{"Tab", "Complete the selected command"},
+		{"Enter", "Run the selected command"},
+		{"F1", "Show this help"},
+		{"Esc", "Cancel"},
+	},
+	helpDetails: {
+		{"Tab, →", "Next tab"},
+		{"Shift+Tab, ←", "Previous tab"},
+		{"1-5", "Overview, Players, Rules, Ping or Timing tab"},
+		{"↑/↓, PgUp/PgDn", "Scroll the tab"},
+		{"/", "Search the rules; Enter or Esc returns to the list"},
+		{"Click a tab", "Switch to it"},
+		{"?, F1", "Show this help"},
+		{"Esc, q", "Back to the server list"},
+	},
+	helpBulk: {
+		{"↑/↓", "Move between actions"},
+		{"Enter", "Run the selected action on the marked servers"},
+		{"A, D", "Add to or remove from favorites; tags are comma separated"},
+		{"E", "Export to a .json or .csv file"},
+		{"C", "Copy the addresses; the terminal must support OSC 52"},
+		{"B", "Block the servers by address"},
+		{"R", "Query the servers again now"},
+		{"X", "Clear the marks"},
+		{"?", "Show this help"},
+		{"Esc", "Cancel"},
+	},
+	helpBlocklist: {
+		{"↑/↓", "Move between entries"},
+		{"A", "Add a block rule"},
+		{"B", "Block the server selected in the server list"},
+		{"D", "Delete the selected rule"},
+		{"V", "Show or hide blocked servers"},
+		{"?", "Show this help"},
+		{"Esc", "Back to the server list"},
+	},
+	helpColumns: {
+		{"Enter, Space", "Show or hide the selected column"},
+		{"</>", "Move the column left or right"},
+		{"-/+", "Narrow or widen the column"},
+		{"R", "Add a column for a server rule"},
+		{"D", "Restore the default columns"},
+		{"A", "Apply the columns"},
+		{"Double-click", "Show or hide a column"},
+		{"?", "Show this help"},
+		{"Esc", "Cancel"},
+	},
+}
+
+// helpText lists the actions of a view with their keys, followed by any
+// keybinding problems
+func (km keymap) helpText(mode ViewMode) string {
+	var b strings.Builder
+	for _, act := range km.actions {
+		if !act.availableIn(mode) {
+			continue
+		}
+		keys := km.keyLabel(act.ID)
+		if keys == "" {
+			keys = "(unbound)"
+		}
+		fmt.Fprintf(&b, "[::b]%-14s[::-] %-40s %s%s[-]\n", tview.Escape(keys), act.Description, mutedTag(), act.ID)
+	}
+	if len(km.problems) > 0 {
+		b.WriteString("\n" + warningTag() + "Keybinding problems:[-]\n")
+		for _, problem := range km.problems {
+			fmt.Fprintf(&b, "  • %s\n", tview.Escape(problem))
+		}
+	}
+	b.WriteString("\n" + mutedTag() + "Dialogs have their own help: ? in lists, F1 in text fields.[-]\n")
+	return b.String()
+}
+
+// dialogHelpText formats the keys of a dialog
+func dialogHelpText(entries []helpEntry) string {
+	var b strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&b, "[::b]%-14s[::-] %s\n", tview.Escape(e.keys), e.description)
+	}
+	return b.String()
+}
+
+// viewName returns the title of a view for the help
+func viewName(mode ViewMode) string {
+	switch mode {
+	case ViewFavorites:
+		return "Favorites"
+	case ViewRecent:
+		return "Recently Played"
+	}
+	return "Master List"
+}
+
+// isHelpKey reports whether event asks for help. Text fields only take F1 so
+// that ? can still be typed.
+func isHelpKey(event *tcell.EventKey, textField bool) bool {
+	if event.Key() == tcell.KeyF1 {
+		return true
+	}
+	return !textField && keyName(event) == "?"
+}
+
+// showHelp lists every action of the current view with its keys
+func (a *App) showHelp() {
+	a.showHelpOverlay("Help: "+viewName(a.viewMode), a.keymap.helpText(a.viewMode), a.layout.Root(), a.layout.Table())
+}
+
+// showDialogHelp lists the keys of a dialog above it
+func (a *App) showDialogHelp(dialog string, root, focus tview.Primitive) {
+	a.showHelpOverlay("Help: "+dialog, dialogHelpText(dialogHelp[dialog]), root, focus)
+}
+
+// showHelpOverlay shows text in a scrollable window above root until Esc, q,
+// ? or F1 is pressed or the mouse clicks outside of it, then returns the
+// keyboard to focus
+func (a *App) showHelpOverlay(title, text string, root, focus tview.Primitive) {
+	view := tview.NewTextView().SetDynamicColors(true).SetScrollable(true).SetWrap(false)
+	view.SetBorder(true).SetTitle(title + " (Esc to close)")
+	view.SetText(text)
+
+	previous := a.app.GetInputCapture()
+	closeHelp := func() {
+		a.app.SetInputCapture(previous)
+		a.app.SetRoot(root, true).SetFocus(focus)
+	}
+	a.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || keyName(event) == "q" || isHelpKey(event, false) {
+			closeHelp()
+			return nil
+		}
+		return event
+	})
+
+	window := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(view, 0, 4, true).
+			AddItem(nil, 0, 1, false), 0, 4, true).
+		AddItem(nil, 0, 1, false)
+
+	// The dialog underneath stays visible but must not receive clicks
+	pages := tview.NewPages().
+		AddPage("base", root, true, true).
+		AddPage("help", window, true, true)
+	pages.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if view.InRect(event.Position()) {
+			return action, event
+		}
+		if action == tview.MouseLeftClick {
+			closeHelp()
+			return tview.MouseConsumed, nil
+		}
+		return action, nil
+	})
+
+	a.app.SetRoot(pages, true).SetFocus(view)
+}
@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/gta"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+// inspectInstallation inspects the GTA directory in the background and
+// renders the result into view
+func (a *App) inspectInstallation(path string, view *tview.TextView) {
+	if path == "" {
+		view.SetText(warningTag() + "GTA SA Path is not set")
+		return
+	}
+	view.SetText(warningTag() + "Inspecting installation...")
+	go func() {
+		inst, err := gta.Inspect(path)
+		a.app.QueueUpdateDraw(func() {
+			view.SetText(formatInstallation(inst, err))
+		})
+	}()
+}
+
+func formatInstallation(inst gta.Installation, err error) string {
+	if err != nil {
+		return badTag() + fmt.Sprintf("✗ %s", tview.Escape(err.Error()))
+	}
+	warnings := inst.Warnings()
+	notes := ""
+	for _, mod := range inst.ModWarnings {
+		notes += fmt.Sprintf("%s; %s: %s[-]", mutedTag(), tview.Escape(mod.Name), tview.Escape(mod.Reason))
+	}
+	if len(warnings) == 0 {
+		return goodTag() + fmt.Sprintf("✓ %s", tview.Escape(inst.Summary())) + notes
+	}
+	return warningTag() + fmt.Sprintf("⚠ %s: %s", tview.Escape(inst.Summary()), tview.Escape(strings.Join(warnings, "; "))) + notes
+}
+
+// launchServer inspects the GTA installation in the background and warns
+// about problems before launching
+func (a *App) launchServer(srv server.Server) {
+	if a.cfg.GTAPath == "" {
+		a.startLaunch(srv)
+		return
+	}
+	if a.inspecting {
+		return
+	}
+	a.inspecting = true
+	a.layout.SetStatus("Checking the GTA installation...")
+	path := a.cfg.GTAPath
+	go func() {
+		inst, err := gta.Inspect(path)
+		a.app.QueueUpdateDraw(func() {
+			a.inspecting = false
+			a.confirmLaunch(srv, inst, err)
+		})
+	}()
+}
+
+// confirmLaunch launches srv, first asking whether to launch anyway when the
+// inspection found problems
+func (a *App) confirmLaunch(srv server.Server, inst gta.Installation, err error) {
+	var warnings []string
+	if err != nil {
+		warnings = []string{err.Error()}
+	} else {
+		warnings = inst.Warnings()
+	}
+	if len(warnings) == 0 {
+		a.startLaunch(srv)
+		return
+	}
+
+	text := "GTA installation problems:\n\n• " + strings.Join(warnings, "\n• ") + "\n\nLaunch anyway?"
+	modal := styleModal(tview.NewModal()).SetText(text).AddButtons([]string{"Launch", "Cancel"})
+	modal.SetDoneFunc(func(_ int, buttonLabel string) {
+		if buttonLabel == "Launch" {
+			a.startLaunch(srv)
+			return
+		}
+		a.setKeybindings()
+		a.app.SetRoot(a.layout.Root(), true)
+		a.app.SetFocus(a.layout.Table())
+	})
+
+	// Clear app-level keybindings so the modal receives Enter
+	a.app.SetInputCapture(nil)
+	a.app.SetRoot(modal, true)
+}
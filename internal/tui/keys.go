@@ -1,3 +1,325 @@
 package tui
 
-const StatusKeys = "[::b]↑↓[::] Navigate  [::b]C[::] Config  [::b]Enter[::] Connect  [::b]/[::] Search  [::b]R[::] Refresh  [::b]S[::] Sort  [::b]V[::] Version  [::b]F[::] Favorites  [::b]A[::] Add Fav  [::b]★[::] Fav Server  [::b]M[::] Master  [::b]Q[::] Quit"
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Action is a command of the server table that can be bound to keys
+type Action struct {
+	ID          string
+	Description string
+	// Label is the status bar text; actions without one are only listed in the help
+	Label string
+	// Labels overrides Label in some views
+	Labels map[ViewMode]string
+	// Keys are the default keys
+	Keys []string
+	// Views limits the action to some views; nil means every view
+	Views []ViewMode
+	Run   func(a *App)
+}
+
+// label returns the status bar text of the action in a view
+func (act *Action) label(mode ViewMode) string {
+	if label, ok := act.Labels[mode]; ok {
+		return label
+	}
+	return act.Label
+}
+
+// availableIn reports whether the action can be used in a view
+func (act *Action) availableIn(mode ViewMode) bool {
+	return act.Views == nil || slices.Contains(act.Views, mode)
+}
+
+var allViews = []ViewMode{ViewMasterList, ViewFavorites, ViewRecent}
+
+// defaultActions lists every bindable action in status bar and help order
+func defaultActions() []Action {
+	return []Action{
+		{ID: "nav_up", Description: "Select the previous server", Label: "Navigate", Keys: []string{"up"}, Run: func(a *App) { a.pressKey(a.layout.Table(), tcell.KeyUp, 0) }},
+		{ID: "nav_down", Description: "Select the next server", Label: "Navigate", Keys: []string{"down"}, Run: func(a *App) { a.pressKey(a.layout.Table(), tcell.KeyDown, 0) }},
+		{ID: "nav_page_up", Description: "Move one page up", Keys: []string{"pgup"}, Run: func(a *App) { a.pressKey(a.layout.Table(), tcell.KeyPgUp, 0) }},
+		{ID: "nav_page_down", Description: "Move one page down", Keys: []string{"pgdn"}, Run: func(a *App) { a.pressKey(a.layout.Table(), tcell.KeyPgDn, 0) }},
+		{ID: "nav_top", Description: "Select the first server", Keys: []string{"home"}, Run: func(a *App) { a.pressKey(a.layout.Table(), tcell.KeyHome, 0) }},
+		{ID: "nav_bottom", Description: "Select the last server", Keys: []string{"end"}, Run: func(a *App) { a.pressKey(a.layout.Table(), tcell.KeyEnd, 0) }},
+		{ID: "config", Description: "Open the configuration", Label: "Config", Keys: []string{"c"}, Run: (*App).showConfigModal},
+		{ID: "connect", Description: "Connect to the selected server", Label: "Connect", Keys: []string{"enter"}, Run: (*App).handleConnect},
+		{ID: "search", Description: "Search by name, address or notes", Label: "Search", Keys: []string{"/"}, Run: (*App).promptSearch},
+		{ID: "refresh", Description: "Refresh the servers of the current view", Label: "Refresh", Keys: []string{"r"}, Run: (*App).refreshView},
+		{ID: "sort_cycle", Description: "Cycle the sort key", Label: "Sort", Keys: []string{"s"}, Run: (*App).cycleSortMode},
+		{ID: "sort_reverse", Description: "Reverse the sort direction", Label: "Sort", Keys: []string{"t"}, Run: (*App).reverseSort},
+		{ID: "sort_secondary", Description: "Cycle the secondary sort key", Label: "Sort", Keys: []string{"b"}, Run: (*App).cycleSecondarySort},
+		{ID: "filter_version", Description: "Filter by version", Label: "Version", Keys: []string{"v"}, Run: (*App).showVersionFilterDialog},
+		{ID: "filter_country", Description: "Filter by country", Label: "Country", Keys: []string{"g"}, Run: (*App).showCountryFilterDialog},
+		{ID: "hide_dead", Description: "Hide or show servers whose last query failed", Label: "Hide Dead", Keys: []string{"e"}, Run: (*App).toggleHideDead},
+		{ID: "columns", Description: "Choose the table columns", Label: "Columns", Keys: []string{"k"}, Run: (*App).showColumnChooser},
+		{ID: "view_favorites", Description: "Switch between the master list and favorites", Label: "Favorites", Labels: map[ViewMode]string{ViewFavorites: "Master List"}, Keys: []string{"f"}, Run: (*App).toggleViewMode},
+		{ID: "view_recent", Description: "Switch between the master list and recently played", Label: "Recent", Labels: map[ViewMode]string{ViewRecent: "Master List"}, Keys: []string{"h"}, Run: (*App).toggleRecentView},
+		{ID: "add_favorite", Description: "Add a favorite by address", Label: "Add Fav", Labels: map[ViewMode]string{ViewFavorites: "Add"}, Keys: []string{"a"}, Views: []ViewMode{ViewMasterList, ViewFavorites}, Run: (*App).addCustomFavorite},
+		{ID: "toggle_favorite", Description: "Add or remove the selected server from favorites", Label: "Fav Server", Keys: []string{"*"}, Views: []ViewMode{ViewMasterList, ViewRecent}, Run: (*App).toggleFavorite},
+		{ID: "remove_favorite", Description: "Remove the selected favorite", Label: "Remove", Keys: []string{"d"}, Views: []ViewMode{ViewFavorites}, Run: (*App).toggleFavorite},
+		{ID: "mod_set", Description: "Assign a mod set to the selected favorite", Label: "Mod Set", Keys: []string{"o"}, Views: []ViewMode{ViewFavorites}, Run: (*App).showModSetPicker},
+		{ID: "master_lists", Description: "Manage master server lists", Label: "Master", Keys: []string{"m"}, Views: []ViewMode{ViewMasterList}, Run: (*App).showMasterListManager},
+		{ID: "show_blocked", Description: "Show blocked servers dimmed or hide them", Keys: []string{"y"}, Views: []ViewMode{ViewMasterList}, Run: (*App).toggleShowBlocked},
+		{ID: "blocklist", Description: "Manage the blocklist", Label: "Blocklist", Keys: []string{"l"}, Run: (*App).showBlocklistManager},
+		{ID: "mark", Description: "Mark or unmark the selected server", Label: "Mark", Keys: []string{"space"}, Run: (*App).toggleMark},
+		{ID: "mark_all", Description: "Mark every shown server", Keys: []string{"ctrl+a"}, Run: (*App).markAll},
+		{ID: "mark_invert", Description: "Invert the marks of the shown servers", Keys: []string{"!"}, Run: (*App).invertMarks},
+		{ID: "mark_clear", Description: "Clear the marks", Keys: []string{"esc"}, Run: (*App).clearMarks},
+		{ID: "bulk", Description: "Act on the marked servers", Label: "Bulk", Keys: []string{"ctrl+b"}, Run: (*App).showBulkMenu},
+		{ID: "next_panel", Description: "Show the next detail panel when they are tabbed", Keys: []string{"tab"}, Run: (*App).nextDetailPanel},
+		{ID: "toggle_details", Description: "Hide or show the detail panels", Label: "Zoom", Keys: []string{"z"}, Run: (*App).toggleDetails},
+		{ID: "details", Description: "Show all details of the selected server", Label: "Details", Keys: []string{"i"}, Run: (*App).showDetails},
+		{ID: "notes", Description: "Edit notes and rating of the selected server", Label: "Notes", Keys: []string{"n"}, Run: (*App).showNotesEditor},
+		{ID: "password", Description: "Enter the password of the selected server", Label: "Password", Keys: []string{"p"}, Run: (*App).promptPassword},
+		{ID: "check_updates", Description: "Check for a new version", Label: "Update", Keys: []string{"u"}, Run: (*App).checkForUpdates},
+		{ID: "diagnostics", Description: "Run environment diagnostics", Label: "Doctor", Keys: []string{"x"}, Run: (*App).showDiagnostics},
+		{ID: "palette", Description: "Open the command palette", Label: "Commands", Keys: []string{":", "ctrl+p"}, Run: (*App).showCommandPalette},
+		{ID: "help", Description: "Show all keybindings", Label: "Help", Keys: []string{"?"}, Run: (*App).showHelp},
+		{ID: "quit", Description: "Quit", Label: "Quit", Keys: []string{"q"}, Run: func(a *App) { a.app.Stop() }},
+	}
+}
+
+// Key presets replace the keys of some actions; the others keep their defaults
+const (
+	PresetDefault = "default"
+	PresetVim     = "vim"
+	PresetArrows  = "arrows"
+)
+
+// KeyPresets lists the presets in the order the config modal offers them
+var KeyPresets = []string{PresetDefault, PresetVim, PresetArrows}
+
+var keyPresets = map[string]map[string][]string{
+	PresetDefault: {},
+	// hjkl-style movement; the column chooser moves off k
+	PresetVim: {
+		"nav_up":        {"k", "up"},
+		"nav_down":      {"j", "down"},
+		"nav_page_up":   {"ctrl+u", "pgup"},
+		"nav_page_down": {"ctrl+d", "pgdn"},
+		"columns":       {"w"},
+	},
+	// The common actions also on arrows and function keys, e.g. for a Steam
+	// Deck; the letters keep working
+	PresetArrows: {
+		"view_favorites": {"right", "f"},
+		"view_recent":    {"left", "h"},
+		"help":           {"f1", "?"},
+		"config":         {"f2", "c"},
+		"search":         {"f3", "/"},
+		"refresh":        {"f5", "r"},
+		"sort_cycle":     {"f6", "s"},
+		"sort_reverse":   {"f7", "t"},
+		"filter_version": {"f8", "v"},
+		"quit":           {"f10", "q"},
+	},
+}
+
+// keyAliases maps other spellings of key names to the ones used by tcell
+var keyAliases = map[string]string{
+	"return":   "enter",
+	"escape":   "esc",
+	"pageup":   "pgup",
+	"pagedown": "pgdn",
+}
+
+// namedKeys maps normalized key names, e.g. "ctrl+r", to their display names
+var namedKeys = func() map[string]string {
+	names := make(map[string]string, len(tcell.KeyNames))
+	for _, name := range tcell.KeyNames {
+		display := strings.Replace(name, "Ctrl-", "Ctrl+", 1)
+		names[strings.ToLower(display)] = display
+	}
+	names["space"] = "Space"
+	return names
+}()
+
+// normalizeKey returns the canonical name of a configured key, or "" if unknown
+func normalizeKey(key string) string {
+	if r, size := utf8.DecodeRuneInString(key); size == len(key) && r != utf8.RuneError && r != ' ' {
+		return string(unicode.ToLower(r))
+	}
+	key = strings.ToLower(strings.TrimSpace(key))
+	key = strings.Replace(key, "ctrl-", "ctrl+", 1)
+	if alias, ok := keyAliases[key]; ok {
+		key = alias
+	}
+	if _, ok := namedKeys[key]; ok {
+		return key
+	}
+	return ""
+}
+
+// keyName returns the canonical name of a pressed key
+func keyName(event *tcell.EventKey) string {
+	if event.Key() == tcell.KeyRune {
+		if event.Rune() == ' ' {
+			return "space"
+		}
+		return string(unicode.ToLower(event.Rune()))
+	}
+	if name, ok := tcell.KeyNames[event.Key()]; ok {
+		return normalizeKey(name)
+	}
+	return ""
+}
+
+// displayKey returns how a key is shown in the status bar and help
+func displayKey(key string) string {
+	switch key {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	}
+	if display, ok := namedKeys[key]; ok {
+		return display
+	}
+	return strings.ToUpper(key)
+}
+
+// keymap resolves keys to actions for each view
+type keymap struct {
+	actions  []*Action
+	bindings map[string][]string
+	views    map[ViewMode]map[string]*Action
+	// problems describes unknown presets, actions and keys, and conflicting bindings
+	problems []string
+}
+
+// buildKeymap binds the actions to the preset's keys, replaced by any overrides.
+// When two actions in the same view share a key, the first one keeps it.
+func buildKeymap(registry []Action, preset string, overrides map[string][]string) keymap {
+	km := keymap{
+		bindings: make(map[string][]string),
+		views:    make(map[ViewMode]map[string]*Action),
+	}
+	if preset == "" {
+		preset = PresetDefault
+	}
+	presetKeys, ok := keyPresets[preset]
+	if !ok {
+		km.problems = append(km.problems, fmt.Sprintf("unknown key preset %q", preset))
+	}
+
+	known := make(map[string]bool, len(registry))
+	for i := range registry {
+		known[registry[i].ID] = true
+	}
+	ids := make([]string, 0, len(overrides))
+	for id := range overrides {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if !known[id] {
+			km.problems = append(km.problems, fmt.Sprintf("unknown action %q", id))
+		}
+	}
+
+	for _, mode := range allViews {
+		km.views[mode] = make(map[string]*Action)
+	}
+	for i := range registry {
+		act := &registry[i]
+		km.actions = append(km.actions, act)
+
+		keys := act.Keys
+		if k, ok := presetKeys[act.ID]; ok {
+			keys = k
+		}
+		if k, ok := overrides[act.ID]; ok {
+			keys = k
+		}
+
+		for _, raw := range keys {
+			key := normalizeKey(raw)
+			if key == "" {
+				km.problems = append(km.problems, fmt.Sprintf("%s: unknown key %q", act.ID, raw))
+				continue
+			}
+			bound := false
+			for _, mode := range allViews {
+				if !act.availableIn(mode) {
+					continue
+				}
+				if other, taken := km.views[mode][key]; taken {
+					if other != act {
+						km.problems = append(km.problems, fmt.Sprintf("%s is bound to both %s and %s; %s keeps it", displayKey(key), other.ID, act.ID, other.ID))
+					}
+					continue
+				}
+				km.views[mode][key] = act
+				bound = true
+			}
+			if bound {
+				km.bindings[act.ID] = append(km.bindings[act.ID], key)
+			}
+		}
+	}
+	km.problems = slices.Compact(km.problems)
+	return km
+}
+
+// lookup returns the action bound to a key in a view
+func (km keymap) lookup(mode ViewMode, key string) (*Action, bool) {
+	act, ok := km.views[mode][key]
+	return act, ok
+}
+
+// keys returns the keys bound to an action
+func (km keymap) keys(id string) []string {
+	return km.bindings[id]
+}
+
+// keyLabel returns the display names of the keys of an action, e.g. "R/F5"
+func (km keymap) keyLabel(id string) string {
+	keys := km.keys(id)
+	labels := make([]string, len(keys))
+	for i, key := range keys {
+		labels[i] = displayKey(key)
+	}
+	return strings.Join(labels, "/")
+}
+
+// statusText returns the status bar hints of a view. Consecutive actions with
+// the same label share one hint, e.g. "S/T/B Sort".
+func (km keymap) statusText(mode ViewMode) string {
+	var parts []string
+	var keys []string
+	label := ""
+	flush := func() {
+		if label != "" && len(keys) > 0 {
+			parts = append(parts, fmt.Sprintf("[::b]%s[::] %s", tview.Escape(strings.Join(keys, "/")), label))
+		}
+		keys = nil
+	}
+	for _, act := range km.actions {
+		if !act.availableIn(mode) || act.label(mode) == "" {
+			continue
+		}
+		if act.label(mode) != label {
+			flush()
+			label = act.label(mode)
+		}
+		if bound := km.keys(act.ID); len(bound) > 0 {
+			keys = append(keys, displayKey(bound[0]))
+		}
+	}
+	flush()
+	return strings.Join(parts, "  ")
+}
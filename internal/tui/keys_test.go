@@ -0,0 +1,54 @@
+package tui
+
+import "testing"
+
+func TestBuildKeymap(t *testing.T) {
+	tests := []struct {
+		preset       string
+		overrides    map[string][]string
+		view         ViewMode
+		key          string
+		wantAction   string
+		wantProblems int
+		description  string
+	}{
+		{"", nil, ViewMasterList, "r", "refresh", 0, "Default key"},
+		{"", nil, ViewMasterList, "d", "", 0, "Action limited to another view"},
+		{"", nil, ViewFavorites, "d", "remove_favorite", 0, "Action in its view"},
+		{"", map[string][]string{"refresh": {"F5"}}, ViewMasterList, "f5", "refresh", 0, "Override with a named key"},
+		{"", map[string][]string{"refresh": {"F5"}}, ViewMasterList, "r", "", 0, "Override replaces the default key"},
+		{"", map[string][]string{"refresh": {"Ctrl-R"}}, ViewMasterList, "ctrl+r", "refresh", 0, "Ctrl key spelled with a dash"},
+		{"", map[string][]string{"search": {"q"}}, ViewMasterList, "q", "search", 1, "Conflict keeps the first action"},
+		{"", map[string][]string{"remove_favorite": {"m"}}, ViewFavorites, "m", "remove_favorite", 0, "No conflict across views"},
+		{"", map[string][]string{"refresh": {"hyper+r"}, "nope": {"z"}}, ViewMasterList, "r", "", 2, "Unknown key and action"},
+		{PresetVim, nil, ViewMasterList, "j", "nav_down", 0, "Vim preset"},
+		{PresetVim, nil, ViewMasterList, "w", "columns", 0, "Vim preset moves the column chooser"},
+		{PresetArrows, nil, ViewMasterList, "right", "view_favorites", 0, "Arrows preset"},
+		{"emacs", nil, ViewMasterList, "r", "refresh", 1, "Unknown preset keeps the defaults"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			km := buildKeymap(defaultActions(), tt.preset, tt.overrides)
+			act, ok := km.lookup(tt.view, tt.key)
+			got := ""
+			if ok {
+				got = act.ID
+			}
+			if got != tt.wantAction {
+				t.Errorf("lookup(%q) = %q, want %q", tt.key, got, tt.wantAction)
+			}
+			if len(km.problems) != tt.wantProblems {
+				t.Errorf("problems = %q, want %d", km.problems, tt.wantProblems)
+			}
+		})
+	}
+}
+
+func TestDefaultKeymapHasNoConflicts(t *testing.T) {
+	for _, preset := range KeyPresets {
+		if km := buildKeymap(defaultActions(), preset, nil); len(km.problems) > 0 {
+			t.Errorf("preset %s: %q", preset, km.problems)
+		}
+	}
+}
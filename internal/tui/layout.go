@@ -2,11 +2,14 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
 	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
 )
 
@@ -16,43 +19,51 @@ type Layout struct {
 	players     *tview.Table
 	rules       *tview.Table
 	pingChart   *tview.TextView
+	trust       *tview.TextView
 	status      *tview.TextView
 	keys        *tview.TextView
 	filterPanel *tview.TextView
 	statusBar   *tview.Flex
 	onSelect    func(row int)
+	onHeader    func(modes []server.SortMode)
+	onActivate  func(row int)
+	columns     []config.Column
+	defs        []columnDef
+	// main holds the table and the detail panels, arranged by Resize
+	main        *tview.Flex
+	splitPanel  *tview.Flex
+	tabbedPanel *tview.Flex
+	panelTabs   *tview.TextView
+	panelPages  *tview.Pages
+	panelTab    int
+	width       int
+	height      int
+	hideDetails bool
+	shape       layoutShape
+	arranged    bool
 }
 
 func NewLayout() *Layout {
 	table := tview.NewTable().SetSelectable(true, false)
 	table.SetBorder(true).SetTitle("Servers")
-	table.SetBordersColor(tcell.ColorWhite)
 	table.SetSeparator(tview.Borders.Vertical)
 
 	players := tview.NewTable().SetSelectable(true, false)
 	players.SetBorder(true).SetTitle("Players")
-	players.SetBordersColor(tcell.ColorWhite)
 	rules := tview.NewTable().SetSelectable(false, false)
 	rules.SetBorder(true).SetTitle("Server Rules")
-	rules.SetBordersColor(tcell.ColorWhite)
 	pingChart := tview.NewTextView().SetDynamicColors(false)
 	pingChart.SetBorder(true).SetTitle("Ping History")
+	trust := tview.NewTextView().SetDynamicColors(true).SetWrap(true)
+	trust.SetBorder(true).SetTitle("Trust")
 	status := tview.NewTextView().SetDynamicColors(true)
 	status.SetText("Ready")
 	keys := tview.NewTextView().SetDynamicColors(true)
-	keys.SetText(StatusKeys)
 	filterPanel := tview.NewTextView().SetDynamicColors(true)
 	filterPanel.SetBorder(true).SetTitle("Filters (V to toggle)")
 	filterPanel.SetText("No version filters active")
 
-	rightPanel := tview.NewFlex().SetDirection(tview.FlexRow)
-	rightPanel.AddItem(players, 0, 1, false)
-	rightPanel.AddItem(rules, 0, 1, false)
-	rightPanel.AddItem(pingChart, 8, 0, false)
-
 	main := tview.NewFlex().SetDirection(tview.FlexColumn)
-	main.AddItem(table, 0, 3, true)
-	main.AddItem(rightPanel, 0, 2, false)
 
 	statusBar := tview.NewFlex().SetDirection(tview.FlexColumn)
 	statusBar.AddItem(status, 0, 1, false)
@@ -69,12 +80,19 @@ func NewLayout() *Layout {
 		players:     players,
 		rules:       rules,
 		pingChart:   pingChart,
+		trust:       trust,
 		status:      status,
 		keys:        keys,
 		filterPanel: filterPanel,
 		statusBar:   statusBar,
+		main:        main,
+		width:       besideMinWidth,
+		height:      splitMinHeight,
 	}
-	layout.initTable()
+	layout.buildDetailPanes()
+	layout.arrange()
+	layout.SetColumns(defaultColumns)
+	layout.ApplyTheme()
 
 	// Set selection change handler
 	table.SetSelectionChangedFunc(func(row, col int) {
@@ -83,9 +101,45 @@ func NewLayout() *Layout {
 		}
 	})
 
+	// The side panels only scroll, so clicking them never takes the keyboard
+	// away from the server table
+	for _, panel := range []*tview.Box{players.Box, rules.Box, pingChart.Box, trust.Box, filterPanel.Box, status.Box, keys.Box} {
+		panel.SetMouseCapture(ignoreClicks)
+	}
+
+	// Clicking a header sorts by its column; double-clicking a row connects
+	table.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if !table.InRect(event.Position()) {
+			return action, event
+		}
+		row, col := table.CellAt(event.Position())
+		switch {
+		case action == tview.MouseLeftClick && row == 0:
+			if col >= 0 && col < len(layout.defs) && len(layout.defs[col].sortModes) > 0 && layout.onHeader != nil {
+				layout.onHeader(layout.defs[col].sortModes)
+			}
+			return tview.MouseConsumed, nil
+		case action == tview.MouseLeftDoubleClick && row > 0:
+			if layout.onActivate != nil {
+				layout.onActivate(row)
+			}
+			return tview.MouseConsumed, nil
+		}
+		return action, event
+	})
+
 	return layout
 }
 
+// ignoreClicks is a mouse capture that drops button presses but keeps scrolling
+func ignoreClicks(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+	switch action {
+	case tview.MouseScrollUp, tview.MouseScrollDown, tview.MouseScrollLeft, tview.MouseScrollRight, tview.MouseMove:
+		return action, event
+	}
+	return action, nil
+}
+
 func (l *Layout) Root() tview.Primitive {
 	return l.root
 }
@@ -98,6 +152,36 @@ func (l *Layout) SetSelectionChangedFunc(f func(row int)) {
 	l.onSelect = f
 }
 
+// ApplyTheme restyles the layout with the active theme
+func (l *Layout) ApplyTheme() {
+	for _, box := range []*tview.Box{l.table.Box, l.players.Box, l.rules.Box, l.pingChart.Box, l.trust.Box, l.status.Box, l.keys.Box, l.filterPanel.Box, l.panelTabs.Box} {
+		box.SetBackgroundColor(theme.Background)
+		box.SetBorderColor(theme.Border)
+		box.SetTitleColor(theme.Title)
+	}
+	for _, table := range []*tview.Table{l.table, l.players, l.rules} {
+		styleTable(table)
+	}
+	for _, view := range []*tview.TextView{l.pingChart, l.trust, l.status, l.keys, l.filterPanel, l.panelTabs} {
+		view.SetTextColor(theme.Text)
+	}
+	for col := 0; col < l.table.GetColumnCount(); col++ {
+		if cell := l.table.GetCell(0, col); cell != nil {
+			cell.SetTextColor(theme.Text)
+		}
+	}
+}
+
+// SetHeaderClickFunc is called with the sort modes of a clicked column header
+func (l *Layout) SetHeaderClickFunc(f func(modes []server.SortMode)) {
+	l.onHeader = f
+}
+
+// SetDoubleClickFunc is called with the table row that was double-clicked
+func (l *Layout) SetDoubleClickFunc(f func(row int)) {
+	l.onActivate = f
+}
+
 func (l *Layout) SetStatus(message string) {
 	l.status.SetText(message)
 }
@@ -111,62 +195,104 @@ func (l *Layout) SetTableTitle(title string) {
 }
 
 func (l *Layout) SetPlayers(players []string, playerCount int) {
+	fillPlayers(l.players, players, playerCount)
+}
+
+func (l *Layout) SetRules(rules map[string]string) {
+	fillRules(l.rules, rules, "")
+}
+
+// fillPlayers lists players in table, or why there are none
+func fillPlayers(table *tview.Table, players []string, playerCount int) {
 	// Clear existing rows
-	l.players.Clear()
+	table.Clear()
 
 	if len(players) == 0 {
 		if playerCount > 0 {
-			l.players.SetCell(0, 0, tview.NewTableCell("Player list unavailable (SA-MP limitation)").SetSelectable(false))
+			table.SetCell(0, 0, tview.NewTableCell("Player list unavailable (SA-MP limitation)").SetSelectable(false))
 		} else {
-			l.players.SetCell(0, 0, tview.NewTableCell("No players online").SetSelectable(false))
+			table.SetCell(0, 0, tview.NewTableCell("No players online").SetSelectable(false))
 		}
 		return
 	}
 
 	// Add header
-	l.players.SetCell(0, 0, tview.NewTableCell("ID").SetTextColor(tcell.ColorYellow).SetSelectable(false))
-	l.players.SetCell(0, 1, tview.NewTableCell("Player Name").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetExpansion(1))
+	table.SetCell(0, 0, tview.NewTableCell("ID").SetTextColor(theme.Header).SetSelectable(false))
+	table.SetCell(0, 1, tview.NewTableCell("Player Name").SetTextColor(theme.Header).SetSelectable(false).SetExpansion(1))
 
 	// Add players
 	for i, name := range players {
 		row := i + 1
-		l.players.SetCell(row, 0, tview.NewTableCell(fmt.Sprintf("%d", i)).SetSelectable(false))
-		l.players.SetCell(row, 1, tview.NewTableCell(name).SetSelectable(false).SetExpansion(1))
+		table.SetCell(row, 0, tview.NewTableCell(fmt.Sprintf("%d", i)).SetSelectable(false))
+		table.SetCell(row, 1, tview.NewTableCell(tview.Escape(name)).SetSelectable(false).SetExpansion(1))
 	}
 }
 
-func (l *Layout) SetRules(rules map[string]string) {
+// fillRules lists the rules whose name or value contains filter, sorted by name
+func fillRules(table *tview.Table, rules map[string]string, filter string) {
 	// Clear existing rows
-	l.rules.Clear()
+	table.Clear()
 
 	if len(rules) == 0 {
-		l.rules.SetCell(0, 0, tview.NewTableCell("No rules available").SetSelectable(false))
+		table.SetCell(0, 0, tview.NewTableCell("No rules available").SetSelectable(false))
 		return
 	}
 
 	// Sort rule names alphabetically
+	filter = strings.ToLower(filter)
 	keys := make([]string, 0, len(rules))
-	for key := range rules {
-		keys = append(keys, key)
+	for key, value := range rules {
+		if strings.Contains(strings.ToLower(key), filter) || strings.Contains(strings.ToLower(value), filter) {
+			keys = append(keys, key)
+		}
 	}
 	sort.Strings(keys)
 
 	// Add header
-	l.rules.SetCell(0, 0, tview.NewTableCell("Rule").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetExpansion(1))
-	l.rules.SetCell(0, 1, tview.NewTableCell("Value").SetTextColor(tcell.ColorYellow).SetSelectable(false).SetExpansion(2))
+	table.SetCell(0, 0, tview.NewTableCell("Rule").SetTextColor(theme.Header).SetSelectable(false).SetExpansion(1))
+	table.SetCell(0, 1, tview.NewTableCell("Value").SetTextColor(theme.Header).SetSelectable(false).SetExpansion(2))
 
 	// Add rules as table rows
 	for i, key := range keys {
 		row := i + 1
-		l.rules.SetCell(row, 0, tview.NewTableCell(key).SetSelectable(false).SetExpansion(1))
-		l.rules.SetCell(row, 1, tview.NewTableCell(rules[key]).SetSelectable(false).SetExpansion(2))
+		table.SetCell(row, 0, tview.NewTableCell(tview.Escape(key)).SetSelectable(false).SetExpansion(1))
+		table.SetCell(row, 1, tview.NewTableCell(tview.Escape(rules[key])).SetSelectable(false).SetExpansion(2))
+	}
+	if len(keys) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("No matching rules").SetSelectable(false))
+	}
+}
+
+// SetTrust shows the trust score of the selected server and why it was lowered
+func (l *Layout) SetTrust(trust server.Trust) {
+	text := fmt.Sprintf("Score: %d/100", trust.Score())
+	if len(trust.Reasons) == 0 {
+		text += " " + mutedTag() + "no fake-player signs"
 	}
+	for _, reason := range trust.Reasons {
+		text += "\n" + warningTag() + "•[-] " + tview.Escape(reason)
+	}
+	l.trust.SetText(text)
+}
+
+// SetPingChart draws the ping history with the statistics of the latest probes
+// and the packet loss over all sent and received probes
+func (l *Layout) SetPingChart(pings []int64, stats server.PingStats, sent, received int) {
+	l.pingChart.SetText(pingChartText(pings, stats, sent, received, 5))
 }
 
-func (l *Layout) SetPingChart(pings []int64) {
+// pingChartText draws a chart of the last 50 pings, height lines high, followed
+// by the statistics
+func pingChartText(pings []int64, stats server.PingStats, sent, received, height int) string {
+	loss := 0.0
+	if sent > 0 {
+		loss = float64(sent-received) * 100 / float64(sent)
+	}
 	if len(pings) == 0 {
-		l.pingChart.SetText("No ping data")
-		return
+		if sent > 0 {
+			return fmt.Sprintf("No ping replies | Loss: %.0f%% of %d probes", loss, sent)
+		}
+		return "No ping data"
 	}
 
 	// Find max ping for scaling
@@ -180,12 +306,8 @@ func (l *Layout) SetPingChart(pings []int64) {
 		maxPing = 1
 	}
 
-	// Create chart (6 lines height)
-	height := 6
-	width := len(pings)
-	if width > 50 {
+	if len(pings) > 50 {
 		pings = pings[len(pings)-50:]
-		width = 50
 	}
 
 	chart := ""
@@ -208,12 +330,16 @@ func (l *Layout) SetPingChart(pings []int64) {
 	}
 
 	// Add labels
-	chart += fmt.Sprintf("Latest: %dms | Avg: %dms | Max: %dms",
+	chart += fmt.Sprintf("Latest: %dms | Avg: %dms | Max: %dms\n",
 		pings[len(pings)-1],
 		average(pings),
 		maxPing)
-
-	l.pingChart.SetText(chart)
+	chart += fmt.Sprintf("Median: %dms | Jitter: %dms | Loss: %.0f%% of %d probes",
+		stats.Median.Milliseconds(),
+		stats.Jitter.Milliseconds(),
+		loss,
+		sent)
+	return chart
 }
 
 func average(nums []int64) int64 {
@@ -227,17 +353,72 @@ func average(nums []int64) int64 {
 	return sum / int64(len(nums))
 }
 
+// SetColumns shows the given columns; the table is rebuilt only when they changed
+func (l *Layout) SetColumns(columns []config.Column) {
+	if slices.Equal(columns, l.columns) {
+		return
+	}
+	l.columns = columns
+	l.defs = make([]columnDef, 0, len(columns))
+	for _, col := range columns {
+		def, _ := lookupColumn(col.ID)
+		l.defs = append(l.defs, def)
+	}
+	l.table.Clear()
+	l.initTable()
+}
+
+// sortColumn returns the table column a sort mode belongs to, or -1
+func (l *Layout) sortColumn(mode server.SortMode) int {
+	for i, def := range l.defs {
+		if slices.Contains(def.sortModes, mode) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetSortIndicator marks the sorted columns in the table header; the secondary
+// key uses a hollow arrow
+func (l *Layout) SetSortIndicator(spec server.SortSpec) {
+	labels := make([]string, len(l.defs))
+	for i, def := range l.defs {
+		labels[i] = def.title
+	}
+	if col := l.sortColumn(spec.Secondary.Mode); col >= 0 && spec.Secondary.Mode != server.SortNone {
+		arrow := "△"
+		if spec.Secondary.Descending {
+			arrow = "▽"
+		}
+		labels[col] += " " + arrow
+	}
+	if col := l.sortColumn(spec.Primary.Mode); col >= 0 {
+		labels[col] += " " + sortArrow(spec.Primary.Descending)
+	}
+	for i, label := range labels {
+		l.table.GetCell(0, i).SetText(fmt.Sprintf("[::b]%s", label))
+	}
+}
+
 func (l *Layout) initTable() {
-	headers := []string{"Name", "Host", "Ping", "Players"}
-	for i, h := range headers {
-		cell := tview.NewTableCell(fmt.Sprintf("[::b]%s", h)).
-			SetSelectable(false).
-			SetExpansion(1)
+	for i, def := range l.defs {
+		cell := tview.NewTableCell(fmt.Sprintf("[::b]%s", tview.Escape(def.title))).
+			SetSelectable(false)
+		l.sizeCell(cell, i)
 		l.table.SetCell(0, i, cell)
 	}
 	l.table.SetFixed(1, 0)
 }
 
+// sizeCell applies the configured width of column i, or its default expansion
+func (l *Layout) sizeCell(cell *tview.TableCell, i int) {
+	if width := l.columns[i].Width; width > 0 {
+		cell.SetMaxWidth(width)
+		return
+	}
+	cell.SetExpansion(l.defs[i].expansion)
+}
+
 func (l *Layout) UpdateTable(servers []server.Server) {
 	// Save current selection
 	row, col := l.table.GetSelection()
@@ -257,27 +438,7 @@ func (l *Layout) UpdateTable(servers []server.Server) {
 
 	// Add all server rows
 	for i, srv := range servers {
-		tableRow := i + 1
-		ping := "-"
-		players := "-"
-		name := srv.Name
-		if name == "" {
-			name = "(unknown)"
-		}
-		if !srv.Loading {
-			ping = fmt.Sprintf("%d ms", srv.Ping.Milliseconds())
-			players = fmt.Sprintf("%d/%d", srv.Players, srv.MaxPlayers)
-		} else if srv.LastUpdated.IsZero() {
-			ping = "..."
-			players = "..."
-		}
-		if srv.Passworded {
-			name = fmt.Sprintf("%s [locked]", name)
-		}
-		l.table.SetCell(tableRow, 0, tview.NewTableCell(name).SetExpansion(2))
-		l.table.SetCell(tableRow, 1, tview.NewTableCell(srv.Addr()).SetExpansion(1))
-		l.table.SetCell(tableRow, 2, tview.NewTableCell(ping).SetExpansion(1))
-		l.table.SetCell(tableRow, 3, tview.NewTableCell(players).SetExpansion(1))
+		l.UpdateTableRow(i, srv)
 	}
 
 	// Restore selection if still valid
@@ -296,32 +457,97 @@ func (l *Layout) UpdateTable(servers []server.Server) {
 
 func (l *Layout) UpdateTableRow(index int, srv server.Server) {
 	tableRow := index + 1
-	ping := "-"
-	players := "-"
+	for i, def := range l.defs {
+		cell := tview.NewTableCell(def.render(srv))
+		l.sizeCell(cell, i)
+		l.table.SetCell(tableRow, i, cell)
+	}
+	if srv.Blocked {
+		l.dimRow(tableRow)
+	}
+	if srv.Marked {
+		l.markRow(tableRow)
+	}
+}
+
+// markRow highlights a table row of the multi-selection; monochrome rows are underlined
+func (l *Layout) markRow(row int) {
+	for col := 0; col < l.table.GetColumnCount(); col++ {
+		if cell := l.table.GetCell(row, col); cell != nil {
+			if theme.Monochrome {
+				cell.SetAttributes(cell.Attributes | tcell.AttrUnderline)
+			} else {
+				cell.SetBackgroundColor(theme.Field)
+			}
+		}
+	}
+}
+
+// dimRow greys out a table row, used for blocked servers
+func (l *Layout) dimRow(row int) {
+	for col := 0; col < l.table.GetColumnCount(); col++ {
+		if cell := l.table.GetCell(row, col); cell != nil {
+			cell.SetTextColor(theme.Muted).SetAttributes(tcell.AttrDim)
+		}
+	}
+}
 
-	// Use alias if available, otherwise use server name
-	name := srv.Name
-	if srv.Alias != "" {
-		name = srv.Alias
+// statusLabel describes why the last query of a server failed, with the
+// number of consecutive failures once it keeps failing. Blocked rows stay dimmed.
+func statusLabel(srv server.Server) string {
+	label := srv.Status.Label()
+	if !srv.Blocked {
+		label = badTag() + label
 	}
-	if name == "" {
-		name = "(unknown)"
+	if srv.Failures > 1 {
+		label += fmt.Sprintf(" ×%d", srv.Failures)
 	}
+	return label
+}
+
+// addrLabel returns host:port, followed by the resolved IP for DNS hostnames
+func addrLabel(srv server.Server) string {
+	ip := srv.IP
+	if ip == "" {
+		ip = server.CachedIP(srv.Host)
+	}
+	// Bracketed IPv6 addresses could otherwise be read as color tags
+	addr := tview.Escape(srv.Addr())
+	if ip == "" || ip == srv.Host {
+		return addr
+	}
+	return fmt.Sprintf("%s %s(%s)[-]", addr, mutedTag(), ip)
+}
 
-	if !srv.Loading {
-		ping = fmt.Sprintf("%d ms", srv.Ping.Milliseconds())
-		players = fmt.Sprintf("%d/%d", srv.Players, srv.MaxPlayers)
-	} else if srv.LastUpdated.IsZero() {
-		ping = "..."
-		players = "..."
+// trustLabel returns the trust score for the table, colored by level
+func trustLabel(srv server.Server) string {
+	score := fmt.Sprintf("%d", srv.Trust.Score())
+	if srv.Blocked {
+		return score
+	}
+	switch srv.Trust.Level() {
+	case server.TrustSuspicious:
+		return badTag() + score
+	case server.TrustQuestionable:
+		return warningTag() + score
 	}
-	if srv.Passworded {
-		name = fmt.Sprintf("%s [locked]", name)
+	return goodTag() + score
+}
+
+// countryLabel returns the country code for the table, or "-" when unknown
+func countryLabel(srv server.Server) string {
+	if srv.Country == "" {
+		return "-"
+	}
+	return srv.Country
+}
+
+// versionLabel returns the exact server version for the table, or "-" when unknown
+func versionLabel(srv server.Server) string {
+	if label := srv.Version.String(); label != "" {
+		return label
 	}
-	l.table.SetCell(tableRow, 0, tview.NewTableCell(name).SetExpansion(2))
-	l.table.SetCell(tableRow, 1, tview.NewTableCell(srv.Addr()).SetExpansion(1))
-	l.table.SetCell(tableRow, 2, tview.NewTableCell(ping).SetExpansion(1))
-	l.table.SetCell(tableRow, 3, tview.NewTableCell(players).SetExpansion(1))
+	return "-"
 }
 
 func (l *Layout) UpdateFilterPanel(text string) {
@@ -22,8 +22,8 @@ func (a *App) showMasterListManager() {
 	}
 
 	table := tview.NewTable().SetSelectable(true, false)
-	table.SetBorder(true).SetTitle("Manage Master Server Lists (Enter: Edit | A: Add | D: Delete | S: Set Active | Esc: Back)")
-	table.SetBordersColor(tcell.ColorWhite)
+	table.SetBorder(true).SetTitle("Manage Master Server Lists (Enter: Edit | A: Add | D: Delete | S: Set Active | ?: Help | Esc: Back)")
+	styleTable(table)
 
 	updateTable := func() {
 		table.Clear()
@@ -60,10 +60,16 @@ func (a *App) showMasterListManager() {
 	// Clear app-level keybindings while in master list manager
 	a.app.SetInputCapture(nil)
 
+	var dialog *tview.Flex
 	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		row, _ := table.GetSelection()
 		idx := row - 1
 
+		if isHelpKey(event, false) {
+			a.showDialogHelp(helpMasterLists, dialog, table)
+			return nil
+		}
+
 		switch event.Key() {
 		case tcell.KeyEscape:
 			a.setKeybindings()
@@ -96,18 +102,12 @@ func (a *App) showMasterListManager() {
 
 			case 's', 'S':
 				if idx >= 0 && idx < len(lists.Lists) {
-					// Set active
-					for i := range lists.Lists {
-						lists.Lists[i].Active = (i == idx)
-					}
-					if err := config.SaveMasterLists(lists); err != nil {
+					if err := a.setActiveMasterList(&lists, idx); err != nil {
 						a.layout.SetStatus(fmt.Sprintf("Failed to save: %v", err))
+					} else {
+						a.layout.SetStatus(fmt.Sprintf("Active master list: %s", lists.Lists[idx].Name))
 					}
-					// Update config
-					a.cfg.MasterServer = lists.Lists[idx].Host
-					config.Save(a.cfg)
 					updateTable()
-					a.layout.SetStatus(fmt.Sprintf("Active master list: %s", lists.Lists[idx].Name))
 				}
 				return nil
 			}
@@ -115,11 +115,33 @@ func (a *App) showMasterListManager() {
 		return event
 	})
 
-	a.app.SetRoot(table, true).SetFocus(table)
+	doubleClickRows(table, func(int) {
+		a.pressKey(table, tcell.KeyEnter, 0)
+	})
+	dialog = a.withButtons(table,
+		keyButton{label: "Edit", key: tcell.KeyEnter},
+		runeButton("Add", 'a'),
+		runeButton("Delete", 'd'),
+		runeButton("Set Active", 's'),
+		keyButton{label: "Back", key: tcell.KeyEscape},
+	)
+	a.app.SetRoot(dialog, true).SetFocus(table)
+}
+
+// setActiveMasterList makes the list at idx the one servers are fetched from
+func (a *App) setActiveMasterList(lists *config.MasterLists, idx int) error {
+	for i := range lists.Lists {
+		lists.Lists[i].Active = i == idx
+	}
+	if err := config.SaveMasterLists(*lists); err != nil {
+		return err
+	}
+	a.cfg.MasterServer = lists.Lists[idx].Host
+	return config.Save(a.cfg)
 }
 
 func (a *App) addMasterList(lists *config.MasterLists, updateTable func()) {
-	form := tview.NewForm()
+	form := styleForm(tview.NewForm())
 	form.SetBorder(true).SetTitle("Add Master Server List")
 
 	var name, host, description string
@@ -138,22 +160,22 @@ func (a *App) addMasterList(lists *config.MasterLists, updateTable func()) {
 
 	form.AddButton("Test", func() {
 		if host == "" {
-			statusText.SetText("[red]Host is required")
+			statusText.SetText(badTag() + "Host is required")
 			return
 		}
 
-		statusText.SetText("[yellow]Testing connection...")
+		statusText.SetText(warningTag() + "Testing connection...")
 		go func() {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 
 			if err := server.TestMasterServer(ctx, host); err != nil {
 				a.app.QueueUpdateDraw(func() {
-					statusText.SetText(fmt.Sprintf("[red]Test failed: %v", err))
+					statusText.SetText(badTag() + fmt.Sprintf("Test failed: %v", err))
 				})
 			} else {
 				a.app.QueueUpdateDraw(func() {
-					statusText.SetText("[green]✓ Connection successful! Valid server list found.")
+					statusText.SetText(goodTag() + "✓ Connection successful! Valid server list found.")
 				})
 			}
 		}()
@@ -161,7 +183,7 @@ func (a *App) addMasterList(lists *config.MasterLists, updateTable func()) {
 
 	form.AddButton("Save", func() {
 		if name == "" || host == "" {
-			statusText.SetText("[red]Name and Host are required")
+			statusText.SetText(badTag() + "Name and Host are required")
 			return
 		}
 
@@ -208,7 +230,7 @@ func (a *App) editMasterList(lists *config.MasterLists, idx int, updateTable fun
 	}
 
 	list := lists.Lists[idx]
-	form := tview.NewForm()
+	form := styleForm(tview.NewForm())
 	form.SetBorder(true).SetTitle("Edit Master Server List")
 
 	statusText := tview.NewTextView().SetDynamicColors(true)
@@ -226,22 +248,22 @@ func (a *App) editMasterList(lists *config.MasterLists, idx int, updateTable fun
 
 	form.AddButton("Test", func() {
 		if list.Host == "" {
-			statusText.SetText("[red]Host is required")
+			statusText.SetText(badTag() + "Host is required")
 			return
 		}
 
-		statusText.SetText("[yellow]Testing connection...")
+		statusText.SetText(warningTag() + "Testing connection...")
 		go func() {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
 
 			if err := server.TestMasterServer(ctx, list.Host); err != nil {
 				a.app.QueueUpdateDraw(func() {
-					statusText.SetText(fmt.Sprintf("[red]Test failed: %v", err))
+					statusText.SetText(badTag() + fmt.Sprintf("Test failed: %v", err))
 				})
 			} else {
 				a.app.QueueUpdateDraw(func() {
-					statusText.SetText("[green]✓ Connection successful! Valid server list found.")
+					statusText.SetText(goodTag() + "✓ Connection successful! Valid server list found.")
 				})
 			}
 		}()
@@ -249,7 +271,7 @@ func (a *App) editMasterList(lists *config.MasterLists, idx int, updateTable fun
 
 	form.AddButton("Save", func() {
 		if list.Name == "" || list.Host == "" {
-			statusText.SetText("[red]Name and Host are required")
+			statusText.SetText(badTag() + "Name and Host are required")
 			return
 		}
 
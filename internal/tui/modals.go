@@ -13,16 +13,11 @@ import (
 )
 
 func (a *App) promptSearch() {
-	input := tview.NewInputField().SetLabel("Search: ").SetText(a.searchQuery)
+	input := styleInput(tview.NewInputField()).SetLabel("Search: ").SetText(a.searchQuery)
 	input.SetDoneFunc(func(key tcell.Key) {
 		if key == tcell.KeyEnter {
 			a.searchQuery = input.GetText()
-			if a.viewMode == ViewFavorites {
-				a.applyFavoritesFilterAndSort()
-				a.layout.UpdateTable(a.filteredFavorites)
-			} else {
-				a.applyFilterAndSort()
-			}
+			a.refreshCurrentView()
 			a.updateFilterPanel()
 		}
 		a.setKeybindings()
@@ -31,10 +26,14 @@ func (a *App) promptSearch() {
 
 	modal := tview.NewFlex().SetDirection(tview.FlexRow)
 	modal.AddItem(input, 3, 0, true)
-	modal.SetBorder(true).SetTitle("Search (Enter to search, Esc to cancel)")
+	modal.SetBorder(true).SetTitle("Search (Enter to search, F1 for help, Esc to cancel)")
 
 	// Clear global keybindings for modal
 	a.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if isHelpKey(event, true) {
+			a.showDialogHelp(helpSearch, modal, input)
+			return nil
+		}
 		if event.Key() == tcell.KeyEscape {
 			a.setKeybindings()
 			a.app.SetRoot(a.layout.Root(), true)
@@ -51,7 +50,12 @@ func (a *App) promptPassword() {
 	if !ok {
 		return
 	}
-	input := tview.NewInputField().SetLabel("Password: ").SetMaskCharacter('*')
+	a.promptPasswordFor(srv)
+}
+
+// promptPasswordFor asks for the password of srv and connects with it
+func (a *App) promptPasswordFor(srv server.Server) {
+	input := styleInput(tview.NewInputField()).SetLabel("Password: ").SetMaskCharacter('*')
 	input.SetDoneFunc(func(key tcell.Key) {
 		if key == tcell.KeyEnter {
 			a.passwords[srv.Addr()] = input.GetText()
@@ -89,7 +93,7 @@ func (a *App) promptAlias(srv server.Server) {
 			ch == '_'
 	}
 
-	input := tview.NewInputField().
+	input := styleInput(tview.NewInputField()).
 		SetLabel("Alias (a-z, 0-9, -, _): ").
 		SetFieldWidth(40).
 		SetAcceptanceFunc(aliasValidator)
@@ -147,7 +151,7 @@ func (a *App) promptAlias(srv server.Server) {
 }
 
 func (a *App) showAddFavoriteDialog() {
-	form := tview.NewForm()
+	form := styleForm(tview.NewForm())
 	form.SetBorder(true).SetTitle("Add Favorite Server (Enter to save, Esc to cancel)")
 
 	var aliasInput, hostInput, portInput *tview.InputField
@@ -161,17 +165,17 @@ func (a *App) showAddFavoriteDialog() {
 			ch == '_'
 	}
 
-	aliasInput = tview.NewInputField().
+	aliasInput = styleInput(tview.NewInputField()).
 		SetLabel("Alias (a-z, 0-9, -, _): ").
 		SetFieldWidth(30).
 		SetAcceptanceFunc(aliasValidator)
 
-	hostInput = tview.NewInputField().
-		SetLabel("Host (IP:Port or IP): ").
+	hostInput = styleInput(tview.NewInputField()).
+		SetLabel("Host (IP:Port, [IPv6]:Port or hostname): ").
 		SetFieldWidth(30).
 		SetAcceptanceFunc(nil)
 
-	portInput = tview.NewInputField().
+	portInput = styleInput(tview.NewInputField()).
 		SetLabel("Port: ").
 		SetFieldWidth(10).
 		SetText("7777").
@@ -191,17 +195,17 @@ func (a *App) showAddFavoriteDialog() {
 			return
 		}
 
-		// Parse host:port format if provided
-		host := hostText
-		port := 7777
+		// Parse host:port, [ipv6]:port or bare IPv6 format if provided
+		host, hostPort, err := server.SplitAddress(hostText)
+		if err != nil {
+			a.layout.SetStatus(err.Error())
+			return
+		}
+		port := server.DefaultPort
 
-		if strings.Contains(hostText, ":") {
-			parts := strings.Split(hostText, ":")
-			host = parts[0]
-			if len(parts) > 1 {
-				if p, err := strconv.Atoi(parts[1]); err == nil {
-					port = p
-				}
+		if hostPort != "" {
+			if p, err := strconv.Atoi(hostPort); err == nil {
+				port = p
 			}
 		} else if portText != "" {
 			if p, err := strconv.Atoi(portText); err == nil {
@@ -233,7 +237,7 @@ func (a *App) showAddFavoriteDialog() {
 
 		displayName := aliasText
 		if displayName == "" {
-			displayName = fmt.Sprintf("%s:%d", host, port)
+			displayName = server.JoinAddr(host, port)
 		}
 		a.layout.SetStatus(fmt.Sprintf("Added %s to favorites", displayName))
 		a.setKeybindings()
@@ -273,17 +277,7 @@ func (a *App) showVersionFilterDialog() {
 		return
 	}
 
-	// Sort versions for consistent display
-	// Simple alphabetical sort
-	for i := 0; i < len(versions); i++ {
-		for j := i + 1; j < len(versions); j++ {
-			if versions[i] > versions[j] {
-				versions[i], versions[j] = versions[j], versions[i]
-			}
-		}
-	}
-
-	list := tview.NewList()
+	list := styleList(tview.NewList())
 	list.SetBorder(true).SetTitle("Version Filter (Space to toggle, Enter to apply, Esc to cancel)")
 
 	// Track temporary filter state
@@ -326,7 +320,7 @@ func (a *App) showVersionFilterDialog() {
 	// Add buttons at the bottom
 	buttons := tview.NewFlex().SetDirection(tview.FlexColumn)
 
-	applyBtn := tview.NewButton("Apply (Enter)")
+	applyBtn := styleButton(tview.NewButton("Apply (Enter)"))
 	applyBtn.SetSelectedFunc(func() {
 		// Apply filters
 		a.versionFilters = make(map[string]bool)
@@ -340,12 +334,7 @@ func (a *App) showVersionFilterDialog() {
 		a.updateFilterPanel()
 
 		// Reapply filters
-		if a.viewMode == ViewFavorites {
-			a.applyFavoritesFilterAndSort()
-			a.layout.UpdateTable(a.filteredFavorites)
-		} else {
-			a.applyFilterAndSort()
-		}
+		a.refreshCurrentView()
 
 		a.setKeybindings()
 		a.app.SetRoot(a.layout.Root(), true)
@@ -359,7 +348,7 @@ func (a *App) showVersionFilterDialog() {
 		a.layout.SetStatus(fmt.Sprintf("Applied %d version filter(s)", activeCount))
 	})
 
-	clearBtn := tview.NewButton("Clear All")
+	clearBtn := styleButton(tview.NewButton("Clear All"))
 	clearBtn.SetSelectedFunc(func() {
 		// Clear all filters
 		tempFilters = make(map[string]bool)
@@ -372,7 +361,7 @@ func (a *App) showVersionFilterDialog() {
 		}
 	})
 
-	cancelBtn := tview.NewButton("Cancel (Esc)")
+	cancelBtn := styleButton(tview.NewButton("Cancel (Esc)"))
 	cancelBtn.SetSelectedFunc(func() {
 		a.setKeybindings()
 		a.app.SetRoot(a.layout.Root(), true)
@@ -405,12 +394,7 @@ func (a *App) showVersionFilterDialog() {
 			a.updateFilterPanel()
 
 			// Reapply filters
-			if a.viewMode == ViewFavorites {
-				a.applyFavoritesFilterAndSort()
-				a.layout.UpdateTable(a.filteredFavorites)
-			} else {
-				a.applyFilterAndSort()
-			}
+			a.refreshCurrentView()
 
 			a.setKeybindings()
 			a.app.SetRoot(a.layout.Root(), true)
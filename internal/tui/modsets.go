@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/modset"
+)
+
+const noModSet = "(none)"
+
+// modSetOptions returns the mod set names for a dropdown, with "(none)" first
+func modSetOptions() []string {
+	options := []string{noModSet}
+	sets, err := config.LoadModSets()
+	if err != nil {
+		return options
+	}
+	for _, set := range sets.Sets {
+		options = append(options, set.Name)
+	}
+	return options
+}
+
+// showModSetPicker assigns a mod set to the selected favorite, previewing
+// the dry-run listing of the highlighted set
+func (a *App) showModSetPicker() {
+	srv, ok := a.selectedServer()
+	if !ok {
+		return
+	}
+	if !config.IsFavorite(srv.Host, srv.Port) {
+		a.layout.SetStatus("Add the server to favorites before assigning a mod set")
+		return
+	}
+
+	current := ""
+	if favorites, err := config.LoadFavorites(); err == nil {
+		for _, fav := range favorites.Servers {
+			if fav.Host == srv.Host && fav.Port == srv.Port {
+				current = fav.ModSet
+			}
+		}
+	}
+
+	options := modSetOptions()
+	if len(options) == 1 {
+		path, _ := config.ModSetsPath()
+		a.layout.SetStatus(fmt.Sprintf("No mod sets configured. Add them to %s", path))
+		return
+	}
+
+	list := styleList(tview.NewList()).ShowSecondaryText(false)
+	list.SetBorder(true).SetTitle(fmt.Sprintf("Mod Set for %s (Enter: Assign | Esc: Cancel)", srv.Addr()))
+
+	preview := tview.NewTextView().SetDynamicColors(false)
+	preview.SetBorder(true).SetTitle("Dry Run")
+
+	showPreview := func(name string) {
+		if name == noModSet {
+			preview.SetText("No files are overlaid; GTA is launched in its vanilla state.")
+			return
+		}
+		set, err := config.FindModSet(name)
+		if err != nil {
+			preview.SetText(err.Error())
+			return
+		}
+		plan, err := modset.BuildPlan(set, a.cfg.GTAPath)
+		if err != nil {
+			preview.SetText(err.Error())
+			return
+		}
+		preview.SetText(plan.String())
+	}
+
+	for _, name := range options {
+		label := name
+		if name == current || (name == noModSet && current == "") {
+			label = "● " + name
+		} else {
+			label = "  " + name
+		}
+		list.AddItem(label, "", 0, nil)
+	}
+	list.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		showPreview(options[index])
+	})
+	list.SetSelectedFunc(func(index int, _ string, _ string, _ rune) {
+		name := options[index]
+		if name == noModSet {
+			name = ""
+		}
+		if err := config.SetFavoriteModSet(srv.Host, srv.Port, name); err != nil {
+			a.layout.SetStatus(fmt.Sprintf("Failed to assign mod set: %v", err))
+		} else if name == "" {
+			a.layout.SetStatus(fmt.Sprintf("Cleared mod set for %s", srv.Addr()))
+		} else {
+			a.layout.SetStatus(fmt.Sprintf("Mod set %q assigned to %s", name, srv.Addr()))
+		}
+		a.setKeybindings()
+		a.app.SetRoot(a.layout.Root(), true)
+		a.app.SetFocus(a.layout.Table())
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			a.setKeybindings()
+			a.app.SetRoot(a.layout.Root(), true)
+			a.app.SetFocus(a.layout.Table())
+			return nil
+		}
+		return event
+	})
+	showPreview(options[0])
+
+	modal := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(list, 0, 1, true).
+		AddItem(preview, 0, 2, false)
+
+	// Clear app-level keybindings while the picker is open
+	a.app.SetInputCapture(nil)
+	a.app.SetRoot(modal, true).SetFocus(list)
+}
@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// keyButton is a clickable stand-in for a key handled by a dialog
+type keyButton struct {
+	label string
+	key   tcell.Key
+	ch    rune
+}
+
+// runeButton returns a button that presses the key ch
+func runeButton(label string, ch rune) keyButton {
+	return keyButton{label: label, key: tcell.KeyRune, ch: ch}
+}
+
+// applyMouse turns mouse support on or off from the config
+func (a *App) applyMouse() {
+	a.app.EnableMouse(!a.cfg.DisableMouse)
+}
+
+// pressKey sends a key to p as if it had been typed while p had focus
+func (a *App) pressKey(p tview.Primitive, key tcell.Key, ch rune) {
+	handler := p.InputHandler()
+	if handler == nil {
+		return
+	}
+	handler(tcell.NewEventKey(key, ch, tcell.ModNone), func(p tview.Primitive) {
+		a.app.SetFocus(p)
+	})
+}
+
+// buttonBar returns a row of buttons that send their key to target, so dialogs
+// driven by the keyboard can also be used with the mouse
+func (a *App) buttonBar(target tview.Primitive, buttons ...keyButton) *tview.Flex {
+	bar := tview.NewFlex().SetDirection(tview.FlexColumn)
+	for _, b := range buttons {
+		b := b
+		button := styleButton(tview.NewButton(b.label))
+		button.SetSelectedFunc(func() {
+			a.pressKey(target, b.key, b.ch)
+			// Give the keyboard back to the dialog unless the key opened another one
+			if a.app.GetFocus() == button {
+				a.app.SetFocus(target)
+			}
+		})
+		bar.AddItem(button, 0, 1, false)
+	}
+	return bar
+}
+
+// withButtons stacks a dialog above its button bar
+func (a *App) withButtons(target tview.Primitive, buttons ...keyButton) *tview.Flex {
+	return tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(target, 0, 1, true).
+		AddItem(a.buttonBar(target, buttons...), 1, 0, false)
+}
+
+// doubleClickRows calls activate with the table row that was double-clicked
+func doubleClickRows(table *tview.Table, activate func(row int)) {
+	table.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if action != tview.MouseLeftDoubleClick || !table.InRect(event.Position()) {
+			return action, event
+		}
+		if row, _ := table.CellAt(event.Position()); row > 0 {
+			activate(row)
+		}
+		return tview.MouseConsumed, nil
+	})
+}
+
+// doubleClickToSelect makes a click only move the cursor of list and a double
+// click select the item, like Enter
+func (a *App) doubleClickToSelect(list *tview.List) {
+	list.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if !list.InRect(event.Position()) {
+			return action, event
+		}
+		switch action {
+		case tview.MouseLeftClick:
+			a.app.SetFocus(list)
+			if index := listIndexAt(list, event); index >= 0 {
+				list.SetCurrentItem(index)
+			}
+			return tview.MouseConsumed, nil
+		case tview.MouseLeftDoubleClick:
+			// The list selects the item under the mouse on a click
+			return tview.MouseLeftClick, event
+		}
+		return action, event
+	})
+}
+
+// listIndexAt returns the index of the single-line list item under the mouse, or -1
+func listIndexAt(list *tview.List, event *tcell.EventMouse) int {
+	_, y := event.Position()
+	_, top, _, height := list.GetInnerRect()
+	if y < top || y >= top+height {
+		return -1
+	}
+	offset, _ := list.GetOffset()
+	index := offset + y - top
+	if index >= list.GetItemCount() {
+		return -1
+	}
+	return index
+}
@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+const lastPlayedLayout = "2006-01-02 15:04"
+
+// loadNotes loads the server notes used for search and the status bar
+func (a *App) loadNotes() {
+	notes, err := config.LoadNotes()
+	if err != nil {
+		a.layout.SetStatus(fmt.Sprintf("Failed to load notes: %v", err))
+		return
+	}
+	a.notes = notes
+}
+
+// noteMatches reports whether the notes of srv contain the lowercase query
+func (a *App) noteMatches(srv server.Server, query string) bool {
+	note, ok := a.notes.Get(srv.Host, srv.Port)
+	return ok && strings.Contains(strings.ToLower(note.Note), query)
+}
+
+// lastPlayedAt returns when srv was last played, from its notes or the launch history
+func (a *App) lastPlayedAt(srv server.Server) time.Time {
+	note, _ := a.notes.Get(srv.Host, srv.Port)
+	if history := a.lastPlayed[srv.Addr()]; history.After(note.LastPlayedAt()) {
+		return history
+	}
+	return note.LastPlayedAt()
+}
+
+// ratingStars renders a rating as filled and empty stars, e.g. "★★★☆☆"
+func ratingStars(rating int) string {
+	if rating <= 0 {
+		return "Unrated"
+	}
+	return strings.Repeat("★", rating) + strings.Repeat("☆", config.MaxRating-rating)
+}
+
+// noteSummary describes the notes of srv in one line, or "" when there are none
+func (a *App) noteSummary(srv server.Server) string {
+	note, ok := a.notes.Get(srv.Host, srv.Port)
+	played := a.lastPlayedAt(srv)
+	if !ok && played.IsZero() {
+		return ""
+	}
+
+	var parts []string
+	if note.Rating > 0 {
+		parts = append(parts, ratingStars(note.Rating))
+	}
+	if !played.IsZero() {
+		parts = append(parts, "Last played "+played.Local().Format(lastPlayedLayout))
+	}
+	if text, _, _ := strings.Cut(strings.TrimSpace(note.Note), "\n"); text != "" {
+		parts = append(parts, tview.Escape(text))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// showNotesEditor edits the notes, rating and last played time of the selected server
+func (a *App) showNotesEditor() {
+	srv, ok := a.selectedServer()
+	if !ok {
+		return
+	}
+
+	// Re-read the file so notes edited by another instance aren't overwritten
+	a.loadNotes()
+	note, _ := a.notes.Get(srv.Host, srv.Port)
+	played := a.lastPlayedAt(srv)
+
+	form := styleForm(tview.NewForm())
+	form.SetBorder(true).SetTitle(fmt.Sprintf("Notes for %s (Esc: Cancel)", tview.Escape(srv.Addr())))
+
+	statusText := tview.NewTextView().SetDynamicColors(true)
+
+	ratings := make([]string, config.MaxRating+1)
+	for i := range ratings {
+		ratings[i] = ratingStars(i)
+	}
+	lastPlayed := ""
+	if !played.IsZero() {
+		lastPlayed = played.Local().Format(lastPlayedLayout)
+	}
+
+	form.AddTextView("Server:", tview.Escape(srv.DisplayName()), 60, 1, true, false)
+	form.AddDropDown("Rating:", ratings, note.Rating, func(_ string, index int) {
+		note.Rating = index
+	})
+	form.AddTextArea("Notes:", note.Note, 60, 6, 0, func(text string) {
+		note.Note = text
+	})
+	form.AddInputField("Last played:", lastPlayed, 20, nil, func(text string) {
+		lastPlayed = text
+	})
+
+	closeEditor := func() {
+		a.setKeybindings()
+		a.app.SetRoot(a.layout.Root(), true)
+		a.app.SetFocus(a.layout.Table())
+	}
+	save := func(updated config.ServerNote, message string) {
+		if err := config.SetServerNote(srv.Host, srv.Port, updated); err != nil {
+			statusText.SetText(badTag() + fmt.Sprintf("Failed to save: %s", tview.Escape(err.Error())))
+			return
+		}
+		a.loadNotes()
+		// Notes are searchable, so the current search may now match differently
+		a.refreshCurrentView()
+		closeEditor()
+		a.layout.SetStatus(message)
+	}
+
+	form.AddButton("Save", func() {
+		note.Note = strings.TrimSpace(note.Note)
+		note.LastPlayed = nil
+		if text := strings.TrimSpace(lastPlayed); text != "" {
+			t, err := time.ParseInLocation(lastPlayedLayout, text, time.Local)
+			if err != nil {
+				statusText.SetText(badTag() + "Last played must look like " + lastPlayedLayout)
+				return
+			}
+			note.LastPlayed = &t
+		}
+		save(note, fmt.Sprintf("Saved notes for %s", srv.Addr()))
+	})
+	form.AddButton("Clear", func() {
+		save(config.ServerNote{}, fmt.Sprintf("Cleared notes for %s", srv.Addr()))
+	})
+	form.AddButton("Cancel", closeEditor)
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			closeEditor()
+			return nil
+		}
+		return event
+	})
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(form, 0, 1, true).
+		AddItem(statusText, 1, 0, false)
+
+	a.app.SetInputCapture(nil)
+	a.app.SetRoot(layout, true).SetFocus(form)
+}
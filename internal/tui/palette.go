@@ -0,0 +1,407 @@
+package tui
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/cli"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+// maxRecentCommands is how many palette commands are remembered
+const maxRecentCommands = 10
+
+// paletteCommand is an entry of the command palette. Most are registry
+// actions; some take arguments, e.g. "connect 1.2.3.4:7777".
+type paletteCommand struct {
+	Name        string
+	Usage       string
+	Description string
+	// Action runs the command without arguments
+	Action *Action
+	// RunArgs runs the command with arguments; nil means it takes none
+	RunArgs func(a *App, args []string) error
+}
+
+// paletteArgCommands lists the commands that take arguments. Those named after
+// an action run that action when given none.
+func paletteArgCommands() []paletteCommand {
+	return []paletteCommand{
+		{Name: "connect", Usage: "connect [address|alias]", RunArgs: (*App).connectCommand},
+		{Name: "search", Usage: "search [text]", RunArgs: (*App).searchCommand},
+		{Name: "sort", Usage: "sort <key> [asc|desc]", Description: "Sort by a key, e.g. sort players desc", RunArgs: (*App).sortCommand},
+		{Name: "view", Usage: "view <master|favorites|recent>", Description: "Switch to a view", RunArgs: (*App).viewCommand},
+		{Name: "master", Usage: "master <name>", Description: "Switch the active master list and refresh", RunArgs: (*App).masterCommand},
+		{Name: "export", Usage: "export <file.json|file.csv>", Description: "Export the marked servers, or all shown servers", RunArgs: (*App).exportCommand},
+	}
+}
+
+// paletteCommands returns the commands available in the current view
+func (a *App) paletteCommands() []paletteCommand {
+	extra := paletteArgCommands()
+	var cmds []paletteCommand
+	for _, act := range a.keymap.actions {
+		if strings.HasPrefix(act.ID, "nav_") || act.ID == "palette" || !act.availableIn(a.viewMode) {
+			continue
+		}
+		cmd := paletteCommand{Name: act.ID, Usage: act.ID, Description: act.Description, Action: act}
+		if i := slices.IndexFunc(extra, func(c paletteCommand) bool { return c.Name == act.ID }); i >= 0 {
+			cmd.Usage = extra[i].Usage
+			cmd.RunArgs = extra[i].RunArgs
+			extra = slices.Delete(extra, i, i+1)
+		}
+		cmds = append(cmds, cmd)
+	}
+	return append(cmds, extra...)
+}
+
+// parseCommandLine splits palette input into a command name and its arguments
+func parseCommandLine(line string) (string, []string) {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), ":"))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return strings.ToLower(fields[0]), fields[1:]
+}
+
+// fuzzyScore matches pattern as a subsequence of text, ignoring case. Runs of
+// consecutive characters, word starts and an exact match score higher.
+func fuzzyScore(pattern, text string) (int, bool) {
+	pattern = strings.ToLower(pattern)
+	text = strings.ToLower(text)
+	score, last, pos := 0, -1, 0
+	for _, r := range pattern {
+		i := strings.IndexRune(text[pos:], r)
+		if i < 0 {
+			return 0, false
+		}
+		i += pos
+		score++
+		if last >= 0 && i == last+1 {
+			score += 3
+		}
+		if i == 0 || strings.ContainsRune("_- ", rune(text[i-1])) {
+			score += 5
+		}
+		last = i
+		pos = i + len(string(r))
+	}
+	if pattern == text {
+		score += 20
+	}
+	return score, true
+}
+
+// rankCommands returns the commands matching query, best match first. Recently
+// used commands come first among equal matches, so an empty query lists them
+// at the top.
+func rankCommands(cmds []paletteCommand, query string, recent []string) []paletteCommand {
+	type ranked struct {
+		cmd     paletteCommand
+		score   int
+		recency int
+	}
+	var matches []ranked
+	for _, cmd := range cmds {
+		score, ok := fuzzyScore(query, cmd.Name)
+		if !ok {
+			// Descriptions only match as a whole word or phrase
+			if !strings.Contains(strings.ToLower(cmd.Description), strings.ToLower(query)) {
+				continue
+			}
+			score = 0
+		}
+		recency := slices.Index(recent, cmd.Name)
+		if recency < 0 {
+			recency = len(recent)
+		}
+		matches = append(matches, ranked{cmd, score, recency})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].recency < matches[j].recency
+	})
+
+	result := make([]paletteCommand, len(matches))
+	for i, m := range matches {
+		result[i] = m.cmd
+	}
+	return result
+}
+
+// runCommand runs a palette command and, once it succeeded, remembers it as recently used
+func (a *App) runCommand(cmd paletteCommand, args []string) error {
+	switch {
+	case len(args) == 0 && cmd.Action != nil:
+		cmd.Action.Run(a)
+	case cmd.RunArgs == nil:
+		return fmt.Errorf("%s takes no arguments", cmd.Name)
+	default:
+		if err := cmd.RunArgs(a, args); err != nil {
+			return err
+		}
+	}
+	a.rememberCommand(cmd.Name)
+	return nil
+}
+
+// rememberCommand moves name to the front of the recently used commands
+func (a *App) rememberCommand(name string) {
+	a.cfg.RecentCommands = slices.DeleteFunc(a.cfg.RecentCommands, func(recent string) bool { return recent == name })
+	a.cfg.RecentCommands = append([]string{name}, a.cfg.RecentCommands...)
+	if len(a.cfg.RecentCommands) > maxRecentCommands {
+		a.cfg.RecentCommands = a.cfg.RecentCommands[:maxRecentCommands]
+	}
+	_ = config.Save(a.cfg)
+}
+
+// showCommandPalette lets the user search every command of the current view
+// and run it, with arguments where the command takes them
+func (a *App) showCommandPalette() {
+	cmds := a.paletteCommands()
+	var shown []paletteCommand
+
+	input := styleInput(tview.NewInputField()).SetLabel(":")
+	list := styleList(tview.NewList()).ShowSecondaryText(false)
+	a.doubleClickToSelect(list)
+
+	update := func(text string) {
+		name, _ := parseCommandLine(text)
+		shown = rankCommands(cmds, name, a.cfg.RecentCommands)
+		list.Clear()
+		for _, cmd := range shown {
+			keys := ""
+			if cmd.Action != nil {
+				keys = a.keymap.keyLabel(cmd.Action.ID)
+			}
+			usage := tview.Escape(fmt.Sprintf("%-32s", cmd.Usage))
+			list.AddItem(fmt.Sprintf("%s %s %s%s[-]", usage, cmd.Description, mutedTag(), tview.Escape(keys)), "", 0, nil)
+		}
+	}
+
+	closePalette := func() {
+		a.setKeybindings()
+		a.app.SetRoot(a.layout.Root(), true)
+		a.app.SetFocus(a.layout.Table())
+	}
+	run := func(index int) {
+		_, args := parseCommandLine(input.GetText())
+		closePalette()
+		if index < 0 || index >= len(shown) {
+			a.layout.SetStatus("No matching command")
+			return
+		}
+		if err := a.runCommand(shown[index], args); err != nil {
+			a.layout.SetStatus(err.Error())
+		}
+	}
+
+	input.SetChangedFunc(update)
+	list.SetSelectedFunc(func(index int, _ string, _ string, _ rune) {
+		run(index)
+	})
+
+	input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		current := list.GetCurrentItem()
+		switch event.Key() {
+		case tcell.KeyEscape:
+			closePalette()
+			return nil
+		case tcell.KeyEnter:
+			run(current)
+			return nil
+		case tcell.KeyUp:
+			list.SetCurrentItem(max(current-1, 0))
+			return nil
+		case tcell.KeyDown:
+			list.SetCurrentItem(min(current+1, list.GetItemCount()-1))
+			return nil
+		case tcell.KeyTab:
+			// Complete the selected command so arguments can follow
+			if current >= 0 && current < len(shown) {
+				input.SetText(shown[current].Name + " ")
+			}
+			return nil
+		}
+		return event
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			closePalette()
+			return nil
+		}
+		return event
+	})
+
+	update("")
+
+	dialog := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(input, 1, 0, true).
+		AddItem(list, 0, 1, false)
+	dialog.SetBorder(true).SetTitle("Commands (Enter: Run | Tab: Complete | F1: Help | Esc: Cancel)")
+
+	a.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if isHelpKey(event, true) {
+			a.showDialogHelp(helpPalette, dialog, a.app.GetFocus())
+			return nil
+		}
+		return event
+	})
+	a.app.SetRoot(dialog, true).SetFocus(input)
+}
+
+// knownServer returns the queried server at host:port from any view
+func (a *App) knownServer(host string, port int) (server.Server, bool) {
+	for _, list := range [][]server.Server{a.servers, a.favorites, a.recent} {
+		for _, srv := range list {
+			if srv.Host == host && srv.Port == port {
+				return srv, true
+			}
+		}
+	}
+	return server.Server{}, false
+}
+
+// connectCommand connects to an address, server URI or favorite alias
+func (a *App) connectCommand(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: connect <address|alias>")
+	}
+	target, err := cli.ResolveTarget(args[0])
+	if err != nil {
+		return err
+	}
+	srv, ok := a.knownServer(target.Host, target.Port)
+	if !ok {
+		srv = server.Server{Host: target.Host, Port: target.Port, Name: cmp.Or(target.Alias, args[0])}
+	}
+	// A server link's password and nickname apply to this launch
+	if target.Password != "" {
+		a.passwords[srv.Addr()] = target.Password
+	}
+	if target.Nickname != "" {
+		a.nicknames[srv.Addr()] = target.Nickname
+	}
+	a.connectTo(srv)
+	return nil
+}
+
+// searchCommand searches the current view without opening the search prompt
+func (a *App) searchCommand(args []string) error {
+	a.searchQuery = strings.Join(args, " ")
+	a.refreshCurrentView()
+	a.updateFilterPanel()
+	return nil
+}
+
+// sortCommand sorts by a key in its natural or the given direction
+func (a *App) sortCommand(args []string) error {
+	names := make([]string, len(server.SortModes))
+	for i, mode := range server.SortModes {
+		names[i] = mode.String()
+	}
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf("usage: sort <%s> [asc|desc]", strings.Join(names, "|"))
+	}
+	key := strings.ToLower(args[0])
+	if !slices.Contains(names, key) {
+		return fmt.Errorf("unknown sort key %q; use one of %s", args[0], strings.Join(names, ", "))
+	}
+	mode := server.ParseSortMode(key)
+	descending := mode.DefaultDescending()
+	if len(args) == 2 {
+		switch strings.ToLower(args[1]) {
+		case "asc":
+			descending = false
+		case "desc":
+			descending = true
+		default:
+			return fmt.Errorf("unknown sort direction %q; use asc or desc", args[1])
+		}
+	}
+
+	a.sortSpec.Primary = server.SortKey{Mode: mode, Descending: descending}
+	if mode == server.SortNone || a.sortSpec.Secondary.Mode == mode {
+		a.sortSpec.Secondary = server.SortKey{}
+	}
+	a.applySort()
+	return nil
+}
+
+// viewCommand switches to a view by name
+func (a *App) viewCommand(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: view <master|favorites|recent>")
+	}
+	switch strings.ToLower(args[0]) {
+	case "master":
+		a.switchView(ViewMasterList)
+	case "favorites":
+		a.switchView(ViewFavorites)
+	case "recent":
+		a.loadRecent()
+		a.switchView(ViewRecent)
+	default:
+		return fmt.Errorf("unknown view %q; use master, favorites or recent", args[0])
+	}
+	return nil
+}
+
+// masterCommand activates a master list by name or host and reloads the servers
+func (a *App) masterCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: master <name>")
+	}
+	lists, err := config.LoadMasterLists()
+	if err != nil {
+		return fmt.Errorf("failed to load master lists: %w", err)
+	}
+	name := strings.Join(args, " ")
+	idx := slices.IndexFunc(lists.Lists, func(list config.MasterList) bool {
+		return strings.EqualFold(list.Name, name) || list.Host == name
+	})
+	if idx < 0 {
+		names := make([]string, len(lists.Lists))
+		for i, list := range lists.Lists {
+			names[i] = list.Name
+		}
+		return fmt.Errorf("unknown master list %q; use one of %s", name, strings.Join(names, ", "))
+	}
+	if err := a.setActiveMasterList(&lists, idx); err != nil {
+		return fmt.Errorf("failed to save: %w", err)
+	}
+	if a.viewMode != ViewMasterList {
+		a.switchView(ViewMasterList)
+	}
+	a.layout.SetStatus(fmt.Sprintf("Active master list: %s", lists.Lists[idx].Name))
+	go a.RefreshServers(true)
+	return nil
+}
+
+// exportCommand writes the marked servers of the current view to a file, or
+// every shown server when none are marked
+func (a *App) exportCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: export <file.json|file.csv>")
+	}
+	servers := a.markedServers()
+	if len(servers) == 0 {
+		servers = a.currentList()
+	}
+	path := strings.Join(args, " ")
+	if err := exportServers(path, servers); err != nil {
+		return err
+	}
+	a.layout.SetStatus(fmt.Sprintf("Exported %d servers to %s", len(servers), path))
+	return nil
+}
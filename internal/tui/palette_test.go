@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+)
+
+func TestRankCommands(t *testing.T) {
+	cmds := []paletteCommand{
+		{Name: "refresh", Description: "Refresh the servers of the current view"},
+		{Name: "sort_cycle", Description: "Cycle the sort key"},
+		{Name: "sort", Description: "Sort by a key"},
+		{Name: "check_updates", Description: "Check for a new version"},
+		{Name: "hide_dead", Description: "Hide or show servers whose last query failed"},
+	}
+
+	tests := []struct {
+		query       string
+		recent      []string
+		want        string
+		wantCount   int
+		description string
+	}{
+		{"", nil, "refresh", 5, "Empty query keeps the registry order"},
+		{"", []string{"hide_dead"}, "hide_dead", 5, "Recent command first"},
+		{"sort", nil, "sort", 2, "Exact match beats a prefix"},
+		{"chup", nil, "check_updates", 1, "Skipped letters"},
+		{"version", nil, "check_updates", 1, "Match in the description"},
+		{"xyz", nil, "", 0, "No match"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			got := rankCommands(cmds, tt.query, tt.recent)
+			if len(got) != tt.wantCount {
+				t.Fatalf("got %d commands, want %d", len(got), tt.wantCount)
+			}
+			if len(got) > 0 && got[0].Name != tt.want {
+				t.Errorf("first = %q, want %q", got[0].Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunCommandRemembersSuccess(t *testing.T) {
+	config.UseTempDirForTest(t)
+
+	ok := paletteCommand{Name: "ok", RunArgs: func(a *App, args []string) error { return nil }}
+	failing := paletteCommand{Name: "failing", RunArgs: func(a *App, args []string) error { return errors.New("bad argument") }}
+	noArgs := paletteCommand{Name: "no_args"}
+
+	tests := []struct {
+		cmd         paletteCommand
+		args        []string
+		wantErr     bool
+		want        []string
+		description string
+	}{
+		{ok, []string{"x"}, false, []string{"ok", "sort"}, "Successful command is remembered first"},
+		{failing, []string{"x"}, true, []string{"sort", "ok"}, "Failing command is not remembered"},
+		{noArgs, []string{"x"}, true, []string{"sort", "ok"}, "Invalid arguments are not remembered"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			a := &App{cfg: config.Config{RecentCommands: []string{"sort", "ok"}}}
+			err := a.runCommand(tt.cmd, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runCommand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(a.cfg.RecentCommands, tt.want) {
+				t.Errorf("RecentCommands = %q, want %q", a.cfg.RecentCommands, tt.want)
+			}
+			saved, err := config.Load()
+			if tt.wantErr {
+				if err == nil && slices.Contains(saved.RecentCommands, tt.cmd.Name) {
+					t.Errorf("saved RecentCommands = %q, want %q left out", saved.RecentCommands, tt.cmd.Name)
+				}
+				return
+			}
+			if err != nil || !slices.Equal(saved.RecentCommands, tt.want) {
+				t.Errorf("saved RecentCommands = %q, %v, want %q", saved.RecentCommands, err, tt.want)
+			}
+		})
+	}
+}
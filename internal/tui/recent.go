@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+// loadRecent builds the recently played list from the launch history,
+// most recent first and with one row per server
+func (a *App) loadRecent() {
+	history, err := config.LoadHistory()
+	if err != nil {
+		return
+	}
+
+	// Reuse data already queried for servers in the other views
+	known := make(map[string]server.Server, len(a.servers)+len(a.favorites))
+	for _, srv := range a.servers {
+		known[srv.Addr()] = srv
+	}
+	for _, srv := range a.favorites {
+		known[srv.Addr()] = srv
+	}
+
+	a.recent = make([]server.Server, 0)
+	a.lastPlayed = make(map[string]time.Time)
+	for i := len(history.Entries) - 1; i >= 0; i-- {
+		entry := history.Entries[i]
+		srv := server.Server{
+			Name:    entry.Name,
+			Host:    entry.Host,
+			Port:    entry.Port,
+			Loading: true,
+		}
+		key := srv.Addr()
+		if _, seen := a.lastPlayed[key]; seen {
+			continue
+		}
+		a.lastPlayed[key] = entry.Timestamp
+		if existing, ok := known[key]; ok {
+			srv = existing
+		}
+		a.recent = append(a.recent, srv)
+	}
+	a.applyRecentFilterAndSort()
+}
+
+func (a *App) applyRecentFilterAndSort() {
+	filtered := make([]server.Server, 0, len(a.recent))
+	query := strings.TrimSpace(strings.ToLower(a.searchQuery))
+	for _, srv := range a.recent {
+		srv = a.annotate(srv)
+		if query != "" && !strings.Contains(strings.ToLower(srv.Name), query) && !strings.Contains(strings.ToLower(srv.Addr()), query) && !tagMatches(srv, query) && !a.noteMatches(srv, query) {
+			continue
+		}
+		if !a.matchesVersionFilter(srv) {
+			continue
+		}
+		if !a.matchesCountryFilter(srv) {
+			continue
+		}
+		if a.hideDead && srv.Dead() {
+			continue
+		}
+		filtered = append(filtered, srv)
+	}
+	server.SortServers(filtered, a.sortSpec)
+	a.filteredRecent = filtered
+}
+
+// updateRecentServer stores fresh query results for a recently played server.
+// It must be called from the UI goroutine.
+func (a *App) updateRecentServer(updated server.Server) {
+	for i := range a.recent {
+		if a.recent[i].Host == updated.Host && a.recent[i].Port == updated.Port {
+			a.recent[i] = updated
+			break
+		}
+	}
+
+	if a.viewMode != ViewRecent {
+		return
+	}
+
+	for i := range a.filteredRecent {
+		if a.filteredRecent[i].Host == updated.Host && a.filteredRecent[i].Port == updated.Port {
+			a.filteredRecent[i] = updated
+			a.layout.UpdateTableRow(i, updated)
+			break
+		}
+	}
+}
+
+// refreshRecent re-queries every recently played server. It must be called
+// from the UI goroutine; the queries themselves run in the background.
+func (a *App) refreshRecent() {
+	a.loadRecent()
+	a.layout.UpdateTable(a.filteredRecent)
+
+	if len(a.recent) == 0 {
+		a.layout.SetStatus("No recently played servers to refresh")
+		return
+	}
+	a.layout.SetStatus("Refreshing recently played servers...")
+
+	recent := make([]server.Server, len(a.recent))
+	copy(recent, a.recent)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var wg sync.WaitGroup
+		for _, srv := range recent {
+			wg.Add(1)
+			go func(srv server.Server) {
+				defer wg.Done()
+				res, err := server.QueryServer(ctx, srv.Host, srv.Port)
+				if err != nil {
+					srv.MarkFailed(err)
+					a.updateServer(srv)
+					return
+				}
+				res.Version.OpenMP = srv.Version.OpenMP
+				if rules, err := server.QueryServerRules(ctx, srv.Host, srv.Port); err == nil {
+					res.SetRules(rules)
+				}
+				res.Alias = srv.Alias
+				a.updateServer(res)
+			}(srv)
+		}
+		wg.Wait()
+
+		a.app.QueueUpdateDraw(func() {
+			a.layout.SetStatus(fmt.Sprintf("Refreshed %d recently played servers", len(recent)))
+		})
+	}()
+}
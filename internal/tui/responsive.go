@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+)
+
+// Terminal sizes at which the layout changes
+const (
+	// besideMinWidth is the narrowest terminal with the detail panels beside the table
+	besideMinWidth = 100
+	// splitMinHeight is the shortest terminal showing every detail panel at
+	// once; shorter ones show them as tabs and the filters on one line
+	splitMinHeight = 30
+)
+
+// detailPlacement is where the detail panels go relative to the server table
+type detailPlacement int
+
+const (
+	detailsBeside detailPlacement = iota
+	detailsBelow
+	detailsHidden
+)
+
+// layoutShape is the arrangement of the main screen for a terminal size
+type layoutShape struct {
+	details detailPlacement
+	// tabbed shows one detail panel at a time with a tab bar
+	tabbed bool
+	// compactFilters shows the filter panel as a single line without a border
+	compactFilters bool
+}
+
+// shapeFor picks the arrangement for a terminal of width x height. Narrow
+// terminals stack the detail panels below the table if they are tall enough,
+// and drop them otherwise.
+func shapeFor(width, height int, hideDetails bool) layoutShape {
+	shape := layoutShape{compactFilters: height < splitMinHeight}
+	switch {
+	case hideDetails:
+		shape.details = detailsHidden
+	case width >= besideMinWidth:
+		shape.details = detailsBeside
+		shape.tabbed = height < splitMinHeight
+	case height >= splitMinHeight:
+		shape.details = detailsBelow
+		shape.tabbed = true
+	default:
+		shape.details = detailsHidden
+	}
+	return shape
+}
+
+// detailPanelNames are the tabs of the tabbed detail panels
+var detailPanelNames = []string{"Players", "Rules", "Trust", "Ping"}
+
+// newTabBar returns a one-line bar with a clickable region per label, named by
+// the label's index
+func newTabBar(labels []string) *tview.TextView {
+	bar := tview.NewTextView().SetDynamicColors(true).SetRegions(true).SetWrap(false)
+	tabs := make([]string, len(labels))
+	for i, label := range labels {
+		tabs[i] = fmt.Sprintf(`["%d"] %s [""]`, i, tview.Escape(label))
+	}
+	bar.SetText(strings.Join(tabs, "│"))
+	bar.Highlight("0")
+	return bar
+}
+
+// buildDetailPanes builds the two containers of the detail panels: all panels
+// stacked, and one panel at a time behind a tab bar
+func (l *Layout) buildDetailPanes() {
+	l.splitPanel = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(l.players, 0, 1, false).
+		AddItem(l.rules, 0, 1, false).
+		AddItem(l.trust, 5, 0, false).
+		AddItem(l.pingChart, 9, 0, false)
+
+	l.panelTabs = newTabBar(detailPanelNames)
+	l.panelPages = tview.NewPages()
+	for i, panel := range []tview.Primitive{l.players, l.rules, l.trust, l.pingChart} {
+		l.panelPages.AddPage(strconv.Itoa(i), panel, true, i == 0)
+	}
+	l.panelTabs.SetHighlightedFunc(func(added, _, _ []string) {
+		if len(added) > 0 {
+			l.panelTab, _ = strconv.Atoi(added[0])
+			l.panelPages.SwitchToPage(added[0])
+		}
+	})
+	l.tabbedPanel = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(l.panelTabs, 1, 0, false).
+		AddItem(l.panelPages, 0, 1, false)
+}
+
+// Resize rearranges the main screen for a terminal of width x height; it is
+// called before every draw and only rebuilds when the arrangement changes
+func (l *Layout) Resize(width, height int) {
+	l.width, l.height = width, height
+	l.arrange()
+}
+
+// SetDetailsHidden hides or shows the detail panels regardless of the terminal size
+func (l *Layout) SetDetailsHidden(hidden bool) {
+	l.hideDetails = hidden
+	l.arrange()
+}
+
+// NextDetailPanel shows the next detail panel when they are tabbed, and
+// reports whether they are
+func (l *Layout) NextDetailPanel() bool {
+	if !l.shape.tabbed || l.shape.details == detailsHidden {
+		return false
+	}
+	l.panelTabs.Highlight(strconv.Itoa((l.panelTab + 1) % len(detailPanelNames)))
+	return true
+}
+
+func (l *Layout) arrange() {
+	shape := shapeFor(l.width, l.height, l.hideDetails)
+	if l.arranged && shape == l.shape {
+		return
+	}
+	l.shape, l.arranged = shape, true
+
+	pane := tview.Primitive(l.splitPanel)
+	if shape.tabbed {
+		pane = l.tabbedPanel
+	}
+	l.main.Clear()
+	switch shape.details {
+	case detailsBeside:
+		l.main.SetDirection(tview.FlexColumn).
+			AddItem(l.table, 0, 3, true).
+			AddItem(pane, 0, 2, false)
+	case detailsBelow:
+		l.main.SetDirection(tview.FlexRow).
+			AddItem(l.table, 0, 3, true).
+			AddItem(pane, 0, 2, false)
+	default:
+		l.main.AddItem(l.table, 0, 1, true)
+	}
+
+	filterHeight := 3
+	if shape.compactFilters {
+		filterHeight = 1
+	}
+	l.filterPanel.SetBorder(!shape.compactFilters)
+	l.root.ResizeItem(l.filterPanel, filterHeight, 0)
+}
+
+// toggleDetails hides or shows the detail panels and remembers the choice
+func (a *App) toggleDetails() {
+	a.cfg.HideDetails = !a.cfg.HideDetails
+	a.layout.SetDetailsHidden(a.cfg.HideDetails)
+	if err := config.Save(a.cfg); err != nil {
+		a.layout.SetStatus(fmt.Sprintf("Failed to save config: %v", err))
+		return
+	}
+	if a.cfg.HideDetails {
+		a.layout.SetStatus("Detail panels hidden")
+	} else {
+		a.layout.SetStatus("Detail panels shown")
+	}
+}
+
+// nextDetailPanel switches the tabbed detail panels
+func (a *App) nextDetailPanel() {
+	if !a.layout.NextDetailPanel() {
+		a.layout.SetStatus("Detail panels are only tabbed on small terminals")
+	}
+}
@@ -0,0 +1,26 @@
+package tui
+
+import "testing"
+
+func TestShapeFor(t *testing.T) {
+	tests := []struct {
+		width, height int
+		hide          bool
+		want          layoutShape
+		description   string
+	}{
+		{160, 50, false, layoutShape{details: detailsBeside}, "Large terminal"},
+		{120, 24, false, layoutShape{details: detailsBeside, tabbed: true, compactFilters: true}, "Wide but short"},
+		{80, 40, false, layoutShape{details: detailsBelow, tabbed: true}, "Narrow but tall"},
+		{80, 24, false, layoutShape{details: detailsHidden, compactFilters: true}, "80x24"},
+		{160, 50, true, layoutShape{details: detailsHidden}, "Hidden by the user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if got := shapeFor(tt.width, tt.height, tt.hide); got != tt.want {
+				t.Errorf("shapeFor(%d, %d, %v) = %+v, want %+v", tt.width, tt.height, tt.hide, got, tt.want)
+			}
+		})
+	}
+}
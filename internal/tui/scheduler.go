@@ -0,0 +1,274 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+const (
+	// Concurrency starts at initialQueryWorkers and adapts to the timeout rate
+	initialQueryWorkers = 64
+	minQueryWorkers     = 16
+	maxQueryWorkers     = 128
+	// adaptEvery is how many finished queries make up one measurement window
+	adaptEvery = 32
+)
+
+// queryScheduler runs one query round at a time. Starting a new round cancels
+// the previous one, and the pending servers of the current round can be
+// reprioritized while it runs. A round that replaces an unfinished one learns
+// which servers were already queried, so it can resume instead of starting over.
+type queryScheduler struct {
+	mu      sync.Mutex
+	current *queryRound
+	cancel  context.CancelFunc
+}
+
+// queryRound queries a set of servers, highest priority first
+type queryRound struct {
+	ctx  context.Context
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	pending map[string]server.Server
+	// order holds the pending addresses, highest priority first
+	order []string
+	// done holds the servers handled by this round, and by the unfinished
+	// rounds it replaced
+	done map[string]bool
+	// resumed holds the servers the replaced rounds had already handled
+	resumed map[string]bool
+
+	active   int
+	limit    int
+	finished int
+	timeouts int
+}
+
+// errNotQueried is returned by a queryHandler that kept the cached data of a
+// server; the server does not count as queried when a later round resumes
+var errNotQueried = errors.New("not queried")
+
+// queryHandler queries one server of a round; resumed reports whether an
+// unfinished round replaced by this one already handled srv
+type queryHandler func(ctx context.Context, srv server.Server, resumed bool) error
+
+// Run queries servers with handle until all are done or the round is replaced
+// by a newer one. priority lists addresses to query first. It reports whether
+// the round completed.
+func (s *queryScheduler) Run(servers []server.Server, priority []string, handle queryHandler) bool {
+	_, completed := s.run(servers, priority, handle, true)
+	return completed
+}
+
+// RunIdle is like Run but leaves a running round alone. It reports whether the
+// round started, which it does only when no other round is running.
+func (s *queryScheduler) RunIdle(servers []server.Server, priority []string, handle queryHandler) (started, completed bool) {
+	return s.run(servers, priority, handle, false)
+}
+
+func (s *queryScheduler) run(servers []server.Server, priority []string, handle queryHandler, replace bool) (started, completed bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	round := &queryRound{
+		ctx:     ctx,
+		pending: make(map[string]server.Server, len(servers)),
+		order:   make([]string, 0, len(servers)),
+		done:    make(map[string]bool),
+		limit:   initialQueryWorkers,
+	}
+	round.cond = sync.NewCond(&round.mu)
+	for _, srv := range servers {
+		key := srv.Addr()
+		if _, dup := round.pending[key]; dup {
+			continue
+		}
+		round.pending[key] = srv
+		round.order = append(round.order, key)
+	}
+	round.prioritize(priority)
+
+	s.mu.Lock()
+	if s.current != nil && !replace {
+		s.mu.Unlock()
+		return false, false
+	}
+	if s.cancel != nil {
+		s.cancel()
+		round.resume(s.current)
+	}
+	s.current = round
+	s.cancel = cancel
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		if s.current == round {
+			s.current = nil
+			s.cancel = nil
+		}
+		s.mu.Unlock()
+	}()
+
+	round.run(handle)
+	return true, ctx.Err() == nil
+}
+
+// Prioritize moves the given addresses to the front of the running round
+func (s *queryScheduler) Prioritize(addrs []string) {
+	s.mu.Lock()
+	round := s.current
+	s.mu.Unlock()
+	if round != nil {
+		round.prioritize(addrs)
+	}
+}
+
+// resume takes over what the cancelled round prev had handled
+func (r *queryRound) resume(prev *queryRound) {
+	prev.mu.Lock()
+	defer prev.mu.Unlock()
+	r.resumed = make(map[string]bool, len(prev.done))
+	for key := range prev.done {
+		r.resumed[key] = true
+		r.done[key] = true
+	}
+}
+
+func (r *queryRound) prioritize(addrs []string) {
+	if len(addrs) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	front := make(map[string]bool, len(addrs))
+	order := make([]string, 0, len(r.order))
+	for _, key := range addrs {
+		if _, ok := r.pending[key]; ok && !front[key] {
+			front[key] = true
+			order = append(order, key)
+		}
+	}
+	// The rest keeps its previous order so the round resumes where it was
+	for _, key := range r.order {
+		if !front[key] {
+			order = append(order, key)
+		}
+	}
+	r.order = order
+}
+
+func (r *queryRound) run(handle queryHandler) {
+	// Wake the dispatcher when the round is cancelled
+	go func() {
+		<-r.ctx.Done()
+		r.mu.Lock()
+		r.cond.Broadcast()
+		r.mu.Unlock()
+	}()
+
+	var wg sync.WaitGroup
+	r.mu.Lock()
+	for {
+		for r.ctx.Err() == nil && (r.active >= r.limit || len(r.order) == 0) && !(len(r.order) == 0 && r.active == 0) {
+			r.cond.Wait()
+		}
+		if r.ctx.Err() != nil || len(r.order) == 0 {
+			break
+		}
+
+		key := r.order[0]
+		r.order = r.order[1:]
+		srv := r.pending[key]
+		delete(r.pending, key)
+		resumed := r.resumed[key]
+		r.active++
+
+		wg.Add(1)
+		go func(srv server.Server) {
+			defer wg.Done()
+			err := handle(r.ctx, srv, resumed)
+
+			r.mu.Lock()
+			r.active--
+			// A query cut short by the cancellation must be repeated by the next round
+			if r.ctx.Err() == nil && err != errNotQueried {
+				r.done[srv.Addr()] = true
+				r.record(err)
+			}
+			r.cond.Broadcast()
+			r.mu.Unlock()
+		}(srv)
+	}
+	r.mu.Unlock()
+	wg.Wait()
+}
+
+// record adapts the concurrency limit: many timeouts usually mean packets are
+// being dropped because too many queries are in flight
+func (r *queryRound) record(err error) {
+	if r.ctx.Err() != nil {
+		return
+	}
+	r.finished++
+	if server.ErrorStatus(err) == server.StatusTimeout {
+		r.timeouts++
+	}
+	if r.finished < adaptEvery {
+		return
+	}
+
+	switch rate := float64(r.timeouts) / float64(r.finished); {
+	case rate > 0.5:
+		r.limit = max(minQueryWorkers, r.limit/2)
+	case rate < 0.2:
+		r.limit = min(maxQueryWorkers, r.limit+8)
+	}
+	r.finished = 0
+	r.timeouts = 0
+}
+
+// queryPriority returns the addresses to query first: the rows visible in the
+// table, then favorites. It must be called from the UI goroutine.
+func (a *App) queryPriority() []string {
+	table := a.layout.Table()
+	list := a.currentList()
+	offset, _ := table.GetOffset()
+	_, _, _, height := table.GetInnerRect()
+
+	addrs := make([]string, 0, height+len(a.favorites))
+	// Skip the header row
+	for i := offset; i < offset+height-1 && i < len(list); i++ {
+		addrs = append(addrs, list[i].Addr())
+	}
+	for _, fav := range a.favorites {
+		addrs = append(addrs, fav.Addr())
+	}
+	return addrs
+}
+
+// uiQueryPriority fetches queryPriority from a background goroutine
+func (a *App) uiQueryPriority() []string {
+	result := make(chan []string, 1)
+	go a.app.QueueUpdate(func() {
+		result <- a.queryPriority()
+	})
+	select {
+	case addrs := <-result:
+		return addrs
+	case <-time.After(time.Second):
+		return nil
+	}
+}
+
+// prioritizeQueries moves what is on screen to the front of the running query
+// round. It must be called from the UI goroutine after scrolling or filtering.
+func (a *App) prioritizeQueries() {
+	a.scheduler.Prioritize(a.queryPriority())
+}
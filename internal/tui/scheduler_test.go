@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+func TestSkipQuery(t *testing.T) {
+	now := time.Now()
+	fresh := server.Server{Status: server.StatusOK, LastUpdated: now.Add(-time.Hour)}
+	stale := server.Server{Status: server.StatusOK, LastUpdated: now.Add(-25 * time.Hour)}
+	dead := server.Server{Status: server.StatusTimeout, LastUpdated: now.Add(-time.Hour), Failures: 1}
+
+	tests := []struct {
+		srv          server.Server
+		forceRefresh bool
+		resumed      bool
+		want         bool
+		description  string
+	}{
+		{fresh, false, false, true, "Fresh cache is kept"},
+		{stale, false, false, false, "Stale cache is queried"},
+		{server.Server{}, false, false, false, "Never queried"},
+		{fresh, true, false, false, "Forced refresh queries fresh servers"},
+		{fresh, true, true, true, "Forced refresh resumes an interrupted round"},
+		{dead, true, true, false, "Resumed dead server is queried again"},
+		{stale, false, true, false, "Resuming does not keep stale cache"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if got := skipQuery(tt.srv, tt.forceRefresh, tt.resumed, now); got != tt.want {
+				t.Errorf("skipQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func testServers(hosts ...string) []server.Server {
+	servers := make([]server.Server, len(hosts))
+	for i, host := range hosts {
+		servers[i] = server.Server{Host: host, Port: 7777}
+	}
+	return servers
+}
+
+// resumedRecorder is a queryHandler that records the resumed flag per host.
+// Hosts in block wait until the round is cancelled; hosts in cached are not queried.
+type resumedRecorder struct {
+	mu      sync.Mutex
+	resumed map[string]bool
+	block   map[string]bool
+	cached  map[string]bool
+}
+
+func (r *resumedRecorder) handle(ctx context.Context, srv server.Server, resumed bool) error {
+	r.mu.Lock()
+	r.resumed[srv.Host] = resumed
+	r.mu.Unlock()
+	if r.block[srv.Host] {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if r.cached[srv.Host] {
+		return errNotQueried
+	}
+	return nil
+}
+
+// waitForHandled waits until the running round of s has handled n servers
+func waitForHandled(t *testing.T, s *queryScheduler, rec *resumedRecorder, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		s.mu.Lock()
+		round := s.current
+		s.mu.Unlock()
+		rec.mu.Lock()
+		seen := len(rec.resumed)
+		rec.mu.Unlock()
+		if round != nil && seen >= n {
+			round.mu.Lock()
+			idle := round.active <= len(rec.block)
+			round.mu.Unlock()
+			if idle {
+				return
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("round did not handle %d servers", n)
+}
+
+func TestSchedulerResumesInterruptedRound(t *testing.T) {
+	var s queryScheduler
+	first := &resumedRecorder{
+		resumed: map[string]bool{},
+		block:   map[string]bool{"d": true},
+		cached:  map[string]bool{"c": true},
+	}
+	done := make(chan bool)
+	go func() {
+		done <- s.Run(testServers("a", "b", "c", "d"), nil, first.handle)
+	}()
+	waitForHandled(t, &s, first, 4)
+
+	// The second round is interrupted too, while querying e
+	second := &resumedRecorder{resumed: map[string]bool{}, block: map[string]bool{"e": true}}
+	go func() {
+		done <- s.Run(testServers("a", "b", "c", "d", "e", "f"), nil, second.handle)
+	}()
+	if <-done {
+		t.Error("first round completed, want it interrupted")
+	}
+	waitForHandled(t, &s, second, 6)
+
+	third := &resumedRecorder{resumed: map[string]bool{}}
+	if !s.Run(testServers("a", "b", "c", "d", "e", "f", "g"), nil, third.handle) {
+		t.Error("third round was interrupted, want it completed")
+	}
+	if <-done {
+		t.Error("second round completed, want it interrupted")
+	}
+
+	wantSecond := map[string]bool{"a": true, "b": true, "c": false, "d": false, "e": false, "f": false}
+	for host, want := range wantSecond {
+		if got := second.resumed[host]; got != want {
+			t.Errorf("second round: resumed[%s] = %v, want %v", host, got, want)
+		}
+	}
+	// The third round resumes both interrupted rounds
+	wantThird := map[string]bool{"a": true, "b": true, "c": true, "d": true, "e": false, "f": true, "g": false}
+	for host, want := range wantThird {
+		if got := third.resumed[host]; got != want {
+			t.Errorf("third round: resumed[%s] = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestSchedulerStartsOverAfterCompletedRound(t *testing.T) {
+	var s queryScheduler
+	first := &resumedRecorder{resumed: map[string]bool{}}
+	if !s.Run(testServers("a", "b"), nil, first.handle) {
+		t.Fatal("first round was interrupted")
+	}
+
+	second := &resumedRecorder{resumed: map[string]bool{}}
+	if !s.Run(testServers("a", "b"), nil, second.handle) {
+		t.Fatal("second round was interrupted")
+	}
+	for host, resumed := range second.resumed {
+		if resumed {
+			t.Errorf("resumed[%s] = true after a completed round, want every server queried", host)
+		}
+	}
+	if len(second.resumed) != 2 {
+		t.Errorf("second round handled %d servers, want 2", len(second.resumed))
+	}
+}
+
+func TestSchedulerRunIdle(t *testing.T) {
+	var s queryScheduler
+	refresh := &resumedRecorder{resumed: map[string]bool{}, block: map[string]bool{"b": true}}
+	done := make(chan bool)
+	go func() {
+		done <- s.Run(testServers("a", "b"), nil, refresh.handle)
+	}()
+	waitForHandled(t, &s, refresh, 2)
+
+	// A bulk re-query does not interrupt the refresh
+	bulk := &resumedRecorder{resumed: map[string]bool{}}
+	if started, _ := s.RunIdle(testServers("a", "c"), nil, bulk.handle); started {
+		t.Fatal("RunIdle() started while a round was running")
+	}
+	if len(bulk.resumed) != 0 {
+		t.Errorf("refused round handled %d servers", len(bulk.resumed))
+	}
+
+	// Once the refresh is replaced and done, the scheduler is idle again
+	if !s.Run(testServers("a", "b"), nil, (&resumedRecorder{resumed: map[string]bool{}}).handle) {
+		t.Fatal("replacing round was interrupted")
+	}
+	if <-done {
+		t.Error("refresh completed, want it interrupted")
+	}
+	started, completed := s.RunIdle(testServers("a", "c"), nil, bulk.handle)
+	if !started || !completed {
+		t.Errorf("RunIdle() = %v, %v on an idle scheduler, want true, true", started, completed)
+	}
+	if len(bulk.resumed) != 2 {
+		t.Errorf("bulk round handled %d servers, want 2", len(bulk.resumed))
+	}
+}
@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+// sortSpecFromConfig restores the persisted sort order
+func sortSpecFromConfig(cfg config.Sort) server.SortSpec {
+	spec := server.SortSpec{
+		Primary:   server.SortKey{Mode: server.ParseSortMode(cfg.Primary), Descending: cfg.Descending},
+		Secondary: server.SortKey{Mode: server.ParseSortMode(cfg.Secondary), Descending: cfg.SecondaryDescending},
+	}
+	if spec.Primary.Mode == server.SortNone || spec.Secondary.Mode == spec.Primary.Mode {
+		spec.Secondary = server.SortKey{}
+	}
+	return spec
+}
+
+// sortLabel describes the sort order for the table title, e.g. "Players ▼, then Ping ▲"
+func sortLabel(spec server.SortSpec) string {
+	if spec.Primary.Mode == server.SortNone {
+		return ""
+	}
+	label := spec.Primary.Mode.Label() + " " + sortArrow(spec.Primary.Descending)
+	if spec.Secondary.Mode != server.SortNone {
+		label += ", then " + spec.Secondary.Mode.Label() + " " + sortArrow(spec.Secondary.Descending)
+	}
+	return label
+}
+
+func sortArrow(descending bool) string {
+	if descending {
+		return "▼"
+	}
+	return "▲"
+}
+
+// nextSortMode returns the mode after current in server.SortModes, skipping skip
+func nextSortMode(current, skip server.SortMode) server.SortMode {
+	modes := server.SortModes
+	start := 0
+	for i, mode := range modes {
+		if mode == current {
+			start = i
+			break
+		}
+	}
+	for i := 1; i <= len(modes); i++ {
+		mode := modes[(start+i)%len(modes)]
+		if mode == server.SortNone || mode != skip {
+			return mode
+		}
+	}
+	return server.SortNone
+}
+
+// cycleSortMode moves to the next primary sort key in its natural direction
+func (a *App) cycleSortMode() {
+	mode := nextSortMode(a.sortSpec.Primary.Mode, server.SortNone)
+	a.sortSpec.Primary = server.SortKey{Mode: mode, Descending: mode.DefaultDescending()}
+	if mode == server.SortNone || a.sortSpec.Secondary.Mode == mode {
+		a.sortSpec.Secondary = server.SortKey{}
+	}
+	a.applySort()
+}
+
+// sortByColumn sorts by the first of a clicked column's modes, or reverses the
+// order when the column is already the primary key
+func (a *App) sortByColumn(modes []server.SortMode) {
+	if slices.Contains(modes, a.sortSpec.Primary.Mode) {
+		a.reverseSort()
+		return
+	}
+	mode := modes[0]
+	a.sortSpec.Primary = server.SortKey{Mode: mode, Descending: mode.DefaultDescending()}
+	if a.sortSpec.Secondary.Mode == mode {
+		a.sortSpec.Secondary = server.SortKey{}
+	}
+	a.applySort()
+}
+
+// reverseSort flips the direction of the primary sort key
+func (a *App) reverseSort() {
+	if a.sortSpec.Primary.Mode == server.SortNone {
+		a.layout.SetStatus("Choose a sort key with S first")
+		return
+	}
+	a.sortSpec.Primary.Descending = !a.sortSpec.Primary.Descending
+	a.applySort()
+}
+
+// cycleSecondarySort moves to the next key used to break ties in the primary key
+func (a *App) cycleSecondarySort() {
+	if a.sortSpec.Primary.Mode == server.SortNone {
+		a.layout.SetStatus("Choose a sort key with S first")
+		return
+	}
+	mode := nextSortMode(a.sortSpec.Secondary.Mode, a.sortSpec.Primary.Mode)
+	a.sortSpec.Secondary = server.SortKey{Mode: mode, Descending: mode.DefaultDescending()}
+	a.applySort()
+}
+
+// applySort re-sorts the current view and persists the sort order
+func (a *App) applySort() {
+	a.refreshCurrentView()
+
+	a.cfg.Sort = config.Sort{
+		Primary:             a.sortSpec.Primary.Mode.String(),
+		Descending:          a.sortSpec.Primary.Descending,
+		Secondary:           a.sortSpec.Secondary.Mode.String(),
+		SecondaryDescending: a.sortSpec.Secondary.Descending,
+	}
+	if err := config.Save(a.cfg); err != nil {
+		a.layout.SetStatus(fmt.Sprintf("Failed to save sort order: %v", err))
+		return
+	}
+
+	if label := sortLabel(a.sortSpec); label != "" {
+		a.layout.SetStatus("Sorted by " + label)
+	} else {
+		a.layout.SetStatus("Sorting disabled")
+	}
+}
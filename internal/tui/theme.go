@@ -0,0 +1,315 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/rsetiawan7/omp-launcher-tui/internal/config"
+)
+
+const (
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+	ThemeMonochrome   = "monochrome"
+)
+
+// Theme is the color palette of the UI
+type Theme struct {
+	Name          string
+	Background    tcell.Color
+	Text          tcell.Color
+	Muted         tcell.Color
+	Border        tcell.Color
+	Title         tcell.Color
+	Header        tcell.Color
+	Selection     tcell.Color
+	SelectionText tcell.Color
+	// Field is the background of input fields and buttons
+	Field    tcell.Color
+	Good     tcell.Color
+	Warning  tcell.Color
+	Bad      tcell.Color
+	Locked   tcell.Color
+	Favorite tcell.Color
+	// Monochrome drops every color and marks the selection with reverse video
+	Monochrome bool
+}
+
+// builtinThemes lists the themes that need no theme file, in the order the config modal offers them
+var builtinThemes = []Theme{
+	{
+		Name:          ThemeDark,
+		Background:    tcell.ColorBlack,
+		Text:          tcell.ColorWhite,
+		Muted:         tcell.ColorGray,
+		Border:        tcell.ColorWhite,
+		Title:         tcell.ColorWhite,
+		Header:        tcell.ColorYellow,
+		Selection:     tcell.ColorWhite,
+		SelectionText: tcell.ColorBlack,
+		Field:         tcell.ColorBlue,
+		Good:          tcell.ColorGreen,
+		Warning:       tcell.ColorYellow,
+		Bad:           tcell.ColorRed,
+		Locked:        tcell.ColorOrange,
+		Favorite:      tcell.ColorYellow,
+	},
+	{
+		Name:          ThemeLight,
+		Background:    tcell.ColorWhite,
+		Text:          tcell.ColorBlack,
+		Muted:         tcell.ColorDimGray,
+		Border:        tcell.ColorDarkSlateGray,
+		Title:         tcell.ColorNavy,
+		Header:        tcell.ColorNavy,
+		Selection:     tcell.ColorNavy,
+		SelectionText: tcell.ColorWhite,
+		Field:         tcell.ColorLightGray,
+		Good:          tcell.ColorGreen,
+		Warning:       tcell.ColorDarkGoldenrod,
+		Bad:           tcell.ColorMaroon,
+		Locked:        tcell.ColorDarkOrange,
+		Favorite:      tcell.ColorDarkGoldenrod,
+	},
+	{
+		Name:          ThemeHighContrast,
+		Background:    tcell.ColorBlack,
+		Text:          tcell.ColorWhite,
+		Muted:         tcell.ColorSilver,
+		Border:        tcell.ColorYellow,
+		Title:         tcell.ColorYellow,
+		Header:        tcell.ColorAqua,
+		Selection:     tcell.ColorYellow,
+		SelectionText: tcell.ColorBlack,
+		Field:         tcell.ColorNavy,
+		Good:          tcell.ColorLime,
+		Warning:       tcell.ColorYellow,
+		Bad:           tcell.ColorRed,
+		Locked:        tcell.ColorFuchsia,
+		Favorite:      tcell.ColorYellow,
+	},
+	{
+		Name:          ThemeMonochrome,
+		Background:    tcell.ColorDefault,
+		Text:          tcell.ColorDefault,
+		Muted:         tcell.ColorDefault,
+		Border:        tcell.ColorDefault,
+		Title:         tcell.ColorDefault,
+		Header:        tcell.ColorDefault,
+		Selection:     tcell.ColorDefault,
+		SelectionText: tcell.ColorDefault,
+		Field:         tcell.ColorDefault,
+		Good:          tcell.ColorDefault,
+		Warning:       tcell.ColorDefault,
+		Bad:           tcell.ColorDefault,
+		Locked:        tcell.ColorDefault,
+		Favorite:      tcell.ColorDefault,
+		Monochrome:    true,
+	},
+}
+
+// theme is the active theme. Like tview.Styles it is read when primitives are built.
+var theme = builtinThemes[0]
+
+// builtinTheme returns the built-in theme called name
+func builtinTheme(name string) (Theme, bool) {
+	i := slices.IndexFunc(builtinThemes, func(t Theme) bool { return t.Name == name })
+	if i < 0 {
+		return Theme{}, false
+	}
+	return builtinThemes[i], true
+}
+
+// themeNames returns the built-in themes followed by the user themes
+func themeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for _, t := range builtinThemes {
+		names = append(names, t.Name)
+	}
+	files, _ := config.ListThemeFiles()
+	for _, name := range files {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// noColor reports whether the NO_COLOR environment variable asks for no colors
+func noColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// loadTheme resolves a theme name from the config; an empty name is the dark
+// theme. NO_COLOR always selects the monochrome theme.
+func loadTheme(name string) (Theme, error) {
+	if noColor() {
+		t, _ := builtinTheme(ThemeMonochrome)
+		return t, nil
+	}
+	if name == "" {
+		name = ThemeDark
+	}
+	if t, ok := builtinTheme(name); ok {
+		return t, nil
+	}
+
+	file, err := config.LoadThemeFile(name)
+	if err != nil {
+		return Theme{}, fmt.Errorf("theme %q: %w", name, err)
+	}
+	base := file.Base
+	if base == "" {
+		base = ThemeDark
+	}
+	t, ok := builtinTheme(base)
+	if !ok {
+		return Theme{}, fmt.Errorf("theme %q: unknown base theme %q", name, base)
+	}
+	t.Name = name
+
+	for _, c := range []struct {
+		value string
+		color *tcell.Color
+	}{
+		{file.Background, &t.Background},
+		{file.Text, &t.Text},
+		{file.Muted, &t.Muted},
+		{file.Border, &t.Border},
+		{file.Title, &t.Title},
+		{file.Header, &t.Header},
+		{file.Selection, &t.Selection},
+		{file.SelectionText, &t.SelectionText},
+		{file.Field, &t.Field},
+		{file.Good, &t.Good},
+		{file.Warning, &t.Warning},
+		{file.Bad, &t.Bad},
+		{file.Locked, &t.Locked},
+		{file.Favorite, &t.Favorite},
+	} {
+		if c.value == "" {
+			continue
+		}
+		color := tcell.GetColor(c.value)
+		if color == tcell.ColorDefault && c.value != "default" {
+			return Theme{}, fmt.Errorf("theme %q: unknown color %q", name, c.value)
+		}
+		*c.color = color
+	}
+	return t, nil
+}
+
+// styles returns the tview defaults for newly built primitives
+func (t Theme) styles() tview.Theme {
+	return tview.Theme{
+		PrimitiveBackgroundColor:    t.Background,
+		ContrastBackgroundColor:     t.Field,
+		MoreContrastBackgroundColor: t.Selection,
+		BorderColor:                 t.Border,
+		TitleColor:                  t.Title,
+		GraphicsColor:               t.Border,
+		PrimaryTextColor:            t.Text,
+		SecondaryTextColor:          t.Header,
+		TertiaryTextColor:           t.Good,
+		InverseTextColor:            t.SelectionText,
+		ContrastSecondaryTextColor:  t.Text,
+	}
+}
+
+// selectedStyle is the style of the selected row of tables and lists
+func (t Theme) selectedStyle() tcell.Style {
+	if t.Monochrome {
+		return tcell.StyleDefault.Reverse(true)
+	}
+	return tcell.StyleDefault.Foreground(t.SelectionText).Background(t.Selection)
+}
+
+// tag returns a color tag for c, or nothing in the monochrome theme
+func (t Theme) tag(c tcell.Color) string {
+	if t.Monochrome || c == tcell.ColorDefault {
+		return ""
+	}
+	return "[" + c.String() + "]"
+}
+
+func goodTag() string    { return theme.tag(theme.Good) }
+func warningTag() string { return theme.tag(theme.Warning) }
+func badTag() string     { return theme.tag(theme.Bad) }
+func mutedTag() string   { return theme.tag(theme.Muted) }
+
+// styleTable applies the theme to a table built outside the main layout
+func styleTable(table *tview.Table) *tview.Table {
+	table.SetBordersColor(theme.Border)
+	table.SetSelectedStyle(theme.selectedStyle())
+	return table
+}
+
+// styleList applies the theme to a list
+func styleList(list *tview.List) *tview.List {
+	list.SetMainTextColor(theme.Text)
+	list.SetSelectedStyle(theme.selectedStyle())
+	return list
+}
+
+// styleForm applies the theme to a form; monochrome fields are underlined and
+// the focused button is reversed
+func styleForm(form *tview.Form) *tview.Form {
+	if theme.Monochrome {
+		form.SetFieldStyle(tcell.StyleDefault.Underline(true))
+		form.SetButtonStyle(tcell.StyleDefault)
+		form.SetButtonActivatedStyle(tcell.StyleDefault.Reverse(true))
+	}
+	return form
+}
+
+// styleButton applies the theme to a standalone button
+func styleButton(button *tview.Button) *tview.Button {
+	if theme.Monochrome {
+		button.SetStyle(tcell.StyleDefault)
+		button.SetActivatedStyle(tcell.StyleDefault.Reverse(true))
+	}
+	return button
+}
+
+// setTheme makes t the active theme for the main layout and every dialog built from now on
+func (a *App) setTheme(t Theme) {
+	theme = t
+	tview.Styles = t.styles()
+	a.layout.ApplyTheme()
+}
+
+// reloadTheme switches to the theme in the config when it changed
+func (a *App) reloadTheme() {
+	t, err := loadTheme(a.cfg.Theme)
+	if err != nil {
+		a.layout.SetStatus(fmt.Sprintf("Failed to load theme: %v", err))
+		return
+	}
+	if t == theme {
+		return
+	}
+	a.setTheme(t)
+	a.refreshCurrentView()
+}
+
+// styleModal applies the theme to a modal's buttons
+func styleModal(modal *tview.Modal) *tview.Modal {
+	if theme.Monochrome {
+		modal.SetButtonStyle(tcell.StyleDefault)
+		modal.SetButtonActivatedStyle(tcell.StyleDefault.Reverse(true))
+	}
+	return modal
+}
+
+// styleInput applies the theme to a standalone input field
+func styleInput(input *tview.InputField) *tview.InputField {
+	if theme.Monochrome {
+		input.SetFieldStyle(tcell.StyleDefault.Underline(true))
+	}
+	return input
+}
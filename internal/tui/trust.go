@@ -0,0 +1,24 @@
+package tui
+
+import (
+	"github.com/rsetiawan7/omp-launcher-tui/internal/server"
+)
+
+// assessTrust runs the fake-player heuristics with the evidence gathered so
+// far: hostnames across the master list and player lists of selected servers.
+// It must be called from the UI goroutine.
+func (a *App) assessTrust(srv server.Server) server.Trust {
+	return server.AssessTrust(srv, a.playerNames[srv.Addr()], a.nameSpread)
+}
+
+// showTrust shows the trust score of srv in the details panel, preferring the
+// master list entry, which also carries the master player count
+func (a *App) showTrust(srv server.Server) {
+	for _, listed := range a.servers {
+		if listed.Host == srv.Host && listed.Port == srv.Port {
+			srv = listed
+			break
+		}
+	}
+	a.layout.SetTrust(a.assessTrust(srv))
+}
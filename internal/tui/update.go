@@ -175,7 +175,7 @@ func (a *App) checkForUpdates() {
 }
 
 func (a *App) showUpdatePrompt(message string, release Release) {
-	modal := tview.NewModal().SetText(message).AddButtons([]string{"Download", "Cancel"})
+	modal := styleModal(tview.NewModal()).SetText(message).AddButtons([]string{"Download", "Cancel"})
 	modal.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
 		if buttonLabel == "Download" {
 			a.downloadAndReplace(release)
@@ -238,7 +238,7 @@ func (a *App) downloadAndReplace(release Release) {
 
 func (a *App) showUpdateInfo(release Release) {
 	text := fmt.Sprintf("%s\n\n%s", release.Tag, release.URL)
-	modal := tview.NewModal().SetText(text).AddButtons([]string{"OK"})
+	modal := styleModal(tview.NewModal()).SetText(text).AddButtons([]string{"OK"})
 	modal.SetDoneFunc(func(_ int, _ string) {
 		a.app.SetRoot(a.layout.Root(), true)
 	})